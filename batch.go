@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mjwong/sudoku2/format"
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+// Result of a puzzle of a batch
+type batchResult struct {
+	Line       int
+	Solutions  int         // 0, 1 or 2 for more than one; only puzzles with one are solved
+	Grid       Intmat      // the solution, or the puzzle if not solved
+	Logic      bool        // solved by the rules without iterMat
+	Counts     map[int]int // steps per rule of the logical solve
+	Iterations int         // of the iterMat fallback, 0 if the rules solved it
+	Elapsed    time.Duration
+}
+
+// Solve the puzzles on a pool of workers, each with its own solver. Calls emit with
+// each result in the order of the puzzles, and returns the results in that order.
+func solveBatch(puzzles []Puzzle, workers int, emit func(batchResult)) []batchResult {
+	if workers < 1 {
+		workers = 1
+	}
+	restore := quiet()
+	defer restore()
+
+	jobs := make(chan int)
+	done := make(chan int)
+	results := make([]batchResult, len(puzzles))
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s := newSolver()
+			for k := range jobs {
+				results[k] = s.solveBatchPuzzle(puzzles[k])
+				done <- k
+			}
+		}()
+	}
+	go func() {
+		for k := range puzzles {
+			jobs <- k
+		}
+		close(jobs)
+		wg.Wait()
+		close(done)
+	}()
+
+	// emit the results in order as the ones before them finish
+	ready := make([]bool, len(puzzles))
+	next := 0
+	for k := range done {
+		ready[k] = true
+		for ; next < len(puzzles) && ready[next]; next++ {
+			if emit != nil {
+				emit(results[next])
+			}
+		}
+	}
+	return results
+}
+
+// Solve a puzzle of a batch with the rules, then iterMat if they get stuck
+func (s *solver) solveBatchPuzzle(p Puzzle) batchResult {
+	r := batchResult{Line: p.Line, Grid: p.Mat}
+	if _, r.Solutions = SolveUnique(p.Mat); r.Solutions != 1 {
+		return r
+	}
+
+	start := time.Now()
+	prof := s.solveLogic(MatToString(p.Mat))
+	r.Counts, r.Logic, r.Grid = prof.Counts, prof.Solved, s.mat
+	if !prof.Solved {
+		s.mat3, s.iterCnt = s.mat, 0
+		s.emptyCnt = s.emptyL.CountNodes()
+		s.iterMat(s.emptyL.Head)
+		r.Grid, r.Iterations = s.mat3, s.iterCnt
+	}
+	r.Elapsed = time.Since(start)
+	return r
+}
+
+// Aggregate statistics of a batch
+type batchStats struct {
+	Puzzles, Solved, Invalid, Multiple int
+	Logic, Fallbacks, Iterations       int
+	Steps, Used                        map[int]int // steps per rule, and puzzles using it
+	Mean, P50, P99                     time.Duration
+}
+
+// Statistics of the results of a batch. Times are of the solved puzzles.
+func batchStatsOf(results []batchResult) batchStats {
+	st := batchStats{Puzzles: len(results), Steps: map[int]int{}, Used: map[int]int{}}
+	var times []time.Duration
+	var total time.Duration
+
+	for _, r := range results {
+		switch r.Solutions {
+		case 0:
+			st.Invalid++
+			continue
+		case 1:
+		default:
+			st.Multiple++
+			continue
+		}
+		if CountEmpty(r.Grid) == 0 {
+			st.Solved++
+		}
+		if r.Logic {
+			st.Logic++
+		} else {
+			st.Fallbacks++
+			st.Iterations += r.Iterations
+		}
+		for rule, cnt := range r.Counts {
+			st.Steps[rule] += cnt
+			st.Used[rule]++
+		}
+		times = append(times, r.Elapsed)
+		total += r.Elapsed
+	}
+
+	if len(times) > 0 {
+		sort.Slice(times, func(a, b int) bool { return times[a] < times[b] })
+		st.Mean = total / time.Duration(len(times))
+		st.P50 = percentile(times, 50)
+		st.P99 = percentile(times, 99)
+	}
+	return st
+}
+
+// Nearest-rank percentile of sorted times
+func percentile(sorted []time.Duration, p int) time.Duration {
+	k := (p*len(sorted)+99)/100 - 1
+	if k < 0 {
+		k = 0
+	}
+	return sorted[k]
+}
+
+// Percentage of n in total, 0 if total is 0
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(n) / float64(total)
+}
+
+// Print the statistics of a batch
+func (st batchStats) print(w io.Writer) {
+	solvable := st.Puzzles - st.Invalid - st.Multiple
+	fmt.Fprintf(w, "Puzzles: %d. Solved: %d. No solution: %d. More than one solution: %d.\n",
+		st.Puzzles, st.Solved, st.Invalid, st.Multiple)
+	fmt.Fprintf(w, "Solved by logic: %d (%.1f%%). iterMat fallbacks: %d", st.Logic, percent(st.Logic, solvable), st.Fallbacks)
+	if st.Fallbacks > 0 {
+		fmt.Fprintf(w, ", %d iterations on average", st.Iterations/st.Fallbacks)
+	}
+	fmt.Fprintln(w, ".")
+	fmt.Fprintf(w, "Time per puzzle: mean %.3f ms, p50 %.3f ms, p99 %.3f ms.\n", millis(st.Mean), millis(st.P50), millis(st.P99))
+
+	fmt.Fprintf(w, "%-16s %8s %8s\n", "Technique", "Steps", "Puzzles")
+	for _, t := range techniques {
+		fmt.Fprintf(w, "%-16s %8d %7.1f%%\n", RuleTable[t.rule], st.Steps[t.rule], percent(st.Used[t.rule], solvable))
+	}
+}
+
+// Solve a batch: sudoku2 [variant flags] batch [-j workers] [-from format] [-q] file
+// Solves the puzzles of the file at the same time on a pool of workers and prints the
+// result of each in the order of the file, then the statistics of the batch. Exits with
+// the highest code of the puzzles as validate does.
+func runBatch(args []string) {
+	fs := newFlagSet("batch")
+	workers := fs.Int("j", runtime.NumCPU(), "Puzzles solved at the same time.")
+	from := fs.String("from", "", "Format of the file: "+strings.Join(format.Names(), ", ")+". Detected if not set.")
+	statsOnly := fs.Bool("q", false, "Print only the statistics.")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 || *workers < 1 {
+		fs.Usage()
+		os.Exit(ExitInvalid)
+	}
+	puzzles, err := format.ReadFile(fs.Arg(0), *from)
+	if err != nil {
+		exitInvalid(err)
+	}
+
+	out := os.Stdout // stdout is the null device while solving
+	log.SetOutput(io.Discard)
+	code := ExitSolved
+	start := time.Now()
+
+	results := solveBatch(puzzles, *workers, func(r batchResult) {
+		var how string
+		switch {
+		case r.Solutions != 1:
+			how = SolutionsText(r.Solutions)
+			code = worstExit(code, solutionsExit(r.Solutions))
+		case r.Logic:
+			how = "logic"
+		default:
+			how = fmt.Sprintf("iterMat %d", r.Iterations)
+		}
+		if CountEmpty(r.Grid) > 0 && r.Solutions == 1 {
+			code = worstExit(code, ExitUnsolved)
+		}
+		if !*statsOnly {
+			fmt.Fprintf(out, "%d: %s %s %.3f ms\n", r.Line, MatToString(r.Grid), how, millis(r.Elapsed))
+		}
+	})
+
+	batchStatsOf(results).print(out)
+	fmt.Fprintf(out, "Workers: %d. Wall time: %.3f ms.\n", *workers, millis(time.Since(start)))
+	os.Exit(code)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mjwong/sudoku2/format"
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+func TestSolveBatch(t *testing.T) {
+	puzzles, err := format.ReadFile("puzzles1.txt", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	puzzles = append(puzzles,
+		Puzzle{Line: 100, Mat: PopulateMat("11...............................................................................")},
+		Puzzle{Line: 101, Mat: PopulateMat("..4.2........873.4...........5.......3....1..........9.42......19....7.....7.3...")})
+
+	var lines []int
+	results := solveBatch(puzzles, 4, func(r batchResult) { lines = append(lines, r.Line) })
+
+	if len(lines) != len(puzzles) || len(results) != len(puzzles) {
+		t.Fatalf("Expected %d results but got %d emitted and %d returned.\n", len(puzzles), len(lines), len(results))
+	}
+	for k, p := range puzzles {
+		if lines[k] != p.Line || results[k].Line != p.Line {
+			t.Fatalf("Result %d: expected line %d but got %d emitted and %d returned.\n", k, p.Line, lines[k], results[k].Line)
+		}
+		if results[k].Solutions != 1 {
+			continue
+		}
+		if sol, _ := SolveUnique(p.Mat); results[k].Grid != sol {
+			t.Fatalf("Line %d: expected the solution\n%s\nbut got\n%s\n", p.Line, MatToString(sol), MatToString(results[k].Grid))
+		}
+	}
+
+	st := batchStatsOf(results)
+	if st.Invalid != 1 || st.Multiple != 1 || st.Solved != len(puzzles)-2 {
+		t.Fatalf("Expected 1 invalid, 1 with more than one solution and %d solved but got %+v.\n", len(puzzles)-2, st)
+	}
+	if st.Logic+st.Fallbacks != st.Solved {
+		t.Fatalf("Expected puzzles solved by logic and by iterMat to add up to %d but got %d and %d.\n", st.Solved, st.Logic, st.Fallbacks)
+	}
+	if st.P50 > st.P99 || st.Mean <= 0 {
+		t.Fatalf("Unexpected times: mean %v, p50 %v, p99 %v.\n", st.Mean, st.P50, st.P99)
+	}
+	if st.Used[3] == 0 || st.Steps[3] < st.Used[3] {
+		t.Fatalf("Expected hidden singles in every solve but got %d steps in %d puzzles.\n", st.Steps[3], st.Used[3])
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mjwong/sudoku2/format"
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+// Puzzle of a booklet with its solution and rating
+type bookletPuzzle struct {
+	Puzzle   Intmat
+	Solution Intmat
+	Rating   Rating
+}
+
+// Layouts of the puzzle pages by puzzles per page: columns and rows of grids
+var bookletLayouts = map[int][2]int{
+	1: {1, 1},
+	2: {1, 2},
+	4: {2, 2},
+	6: {2, 3},
+}
+
+// Solutions are printed small at the back, 3 columns of 4
+var solutionLayout = [2]int{3, 4}
+
+// Make a booklet: sudoku2 [variant flags] booklet [-title t] [-per 4] [-from sdk] [-o file.tex] file
+// Solves and rates each puzzle of the file and writes a LaTeX booklet of the puzzles with
+// their difficulty and the solutions at the back. Writes to stdout without -o.
+func runBooklet(args []string) {
+	s := newSolver()
+
+	fs := newFlagSet("booklet")
+	title := fs.String("title", "Sudoku", "Title of the booklet.")
+	per := fs.Int("per", 4, "Puzzles per page: 1, 2, 4 or 6.")
+	from := fs.String("from", "", "Format of the file: "+strings.Join(format.Names(), ", ")+". Detected if not set.")
+	out := fs.String("o", "", "Output file, stdout if not set.")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "booklet: expected a puzzle file")
+		os.Exit(ExitInvalid)
+	}
+	if _, ok := bookletLayouts[*per]; !ok {
+		fmt.Fprintf(os.Stderr, "booklet: cannot print %d puzzles per page, expected 1, 2, 4 or 6\n", *per)
+		os.Exit(ExitInvalid)
+	}
+	puzzles, err := format.ReadFile(fs.Arg(0), *from)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(ExitInvalid)
+	}
+
+	var list []bookletPuzzle
+	for _, p := range puzzles {
+		sol, cnt := SolveUnique(p.Mat)
+		if cnt != 1 {
+			fmt.Fprintf(os.Stderr, "%s: line %d: puzzle has %s\n", fs.Arg(0), p.Line, SolutionsText(cnt))
+			os.Exit(solutionsExit(cnt))
+		}
+		r := rate(s.solveLogic(MatToString(p.Mat)))
+		list = append(list, bookletPuzzle{Puzzle: p.Mat, Solution: sol, Rating: r})
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		if w, err = os.Create(*out); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(ExitUnsolved)
+		}
+		defer w.Close()
+	}
+	if err := writeBooklet(w, *title, *per, list); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(ExitUnsolved)
+	}
+}
+
+// Write the LaTeX document of the booklet. The grids are drawn with TikZ, so the
+// document builds with pdflatex and no other packages than tikz and geometry.
+func writeBooklet(w io.Writer, title string, per int, list []bookletPuzzle) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, `\documentclass[a4paper]{article}`)
+	fmt.Fprintln(bw, `\usepackage[margin=1.5cm]{geometry}`)
+	fmt.Fprintln(bw, `\usepackage{tikz}`)
+	fmt.Fprintln(bw, `\pagestyle{empty}`)
+	fmt.Fprintln(bw, `\begin{document}`)
+	fmt.Fprintf(bw, "\\begin{center}{\\Huge\\bfseries %s}\\end{center}\n", texEscape(title))
+
+	var grids, sols []string
+	for k, p := range list {
+		label := fmt.Sprintf("Puzzle %d \\quad %s (ER %.1f)", k+1, p.Rating.Band, p.Rating.ER)
+		grids = append(grids, texGrid(label, p.Puzzle, p.Puzzle, bookletLayouts[per]))
+		sols = append(sols, texGrid(fmt.Sprintf("Puzzle %d", k+1), p.Solution, p.Puzzle, solutionLayout))
+	}
+	writePages(bw, grids, bookletLayouts[per])
+
+	if len(sols) > 0 {
+		fmt.Fprintln(bw, `\newpage`)
+		fmt.Fprintln(bw, `\begin{center}{\Large\bfseries Solutions}\end{center}`)
+		writePages(bw, sols, solutionLayout)
+	}
+
+	fmt.Fprintln(bw, `\end{document}`)
+	return bw.Flush()
+}
+
+// Write the grids in rows of the layout, a page at a time
+func writePages(w io.Writer, grids []string, layout [2]int) {
+	cols, rows := layout[0], layout[1]
+	for k, g := range grids {
+		switch {
+		case k > 0 && k%(cols*rows) == 0:
+			fmt.Fprintln(w, `\newpage`)
+		case k%cols == 0:
+			fmt.Fprintln(w, `\par\bigskip`)
+		}
+		if k%(cols*rows) == 0 {
+			fmt.Fprintln(w, `\noindent`)
+		}
+		fmt.Fprint(w, g)
+		if k%cols != cols-1 {
+			fmt.Fprintln(w, `\hfill`)
+		}
+	}
+}
+
+// TikZ picture of the grid in a minipage with the label above it. Cells where givens is
+// 0 are solved digits, printed in grey. The grid is as big as fits the layout on a page.
+func texGrid(label string, m, givens Intmat, layout [2]int) string {
+	cols, rows := layout[0], layout[1]
+	width := minFloat(17.5/float64(cols)-0.5, 24.5/float64(rows)-1.5)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\\begin{minipage}{%.2fcm}\\centering\n", width)
+	fmt.Fprintf(&b, "\\textbf{%s}\\par\\smallskip\n", label)
+	fmt.Fprintf(&b, "\\begin{tikzpicture}[x=%.3fcm,y=%.3fcm]\n", width/N, width/N)
+	fmt.Fprintf(&b, "\\draw[gray!60] (0,0) grid (%d,%d);\n", N, N)
+
+	// thick lines between boxes or jigsaw regions
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			if j < N-1 && BlkOf(i, j) != BlkOf(i, j+1) {
+				fmt.Fprintf(&b, "\\draw[line width=1.2pt] (%d,%d) -- (%d,%d);\n", j+1, N-i, j+1, N-i-1)
+			}
+			if i < N-1 && BlkOf(i, j) != BlkOf(i+1, j) {
+				fmt.Fprintf(&b, "\\draw[line width=1.2pt] (%d,%d) -- (%d,%d);\n", j, N-i-1, j+1, N-i-1)
+			}
+		}
+	}
+	fmt.Fprintf(&b, "\\draw[line width=1.6pt] (0,0) rectangle (%d,%d);\n", N, N)
+
+	size := `\Large`
+	if width < 7 {
+		size = `\small`
+	}
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			if m[i][j] == 0 {
+				continue
+			}
+			style := ""
+			if givens[i][j] == 0 {
+				style = "[gray]"
+			}
+			fmt.Fprintf(&b, "\\node%s at (%d.5,%d.5) {%s %d};\n", style, j, N-i-1, size, m[i][j])
+		}
+	}
+
+	b.WriteString("\\end{tikzpicture}\n\\end{minipage}\n")
+	return b.String()
+}
+
+// Escape the special characters of LaTeX in text
+func texEscape(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\textbackslash{}`,
+		`{`, `\{`, `}`, `\}`, `$`, `\$`, `&`, `\&`, `#`, `\#`, `%`, `\%`, `_`, `\_`,
+		`^`, `\textasciicircum{}`, `~`, `\textasciitilde{}`)
+	return r.Replace(s)
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+func TestBooklet(t *testing.T) {
+	s := newSolver()
+
+	var list []bookletPuzzle
+	for _, input := range []string{
+		"...15....91..764..5.6.4.3........69.6..5.4..7.71........7.3.9.6..386..15....95...",
+		"14...3.......4...38.3.52.......2..977.6.9.4.545..6.......43.1.29...8.......6...39",
+		"3.1.64.8..5.17.4.........7.....5.8..4...3...5..7.9.....4.........9.26.3..1.84.2.7",
+	} {
+		m := PopulateMat(input)
+		sol, _ := SolveUnique(m)
+		list = append(list, bookletPuzzle{Puzzle: m, Solution: sol, Rating: rate(s.solveLogic(input))})
+	}
+
+	var b bytes.Buffer
+	if err := writeBooklet(&b, "Weekly #1 & more", 2, list); err != nil {
+		t.Fatal(err)
+	}
+	doc := b.String()
+
+	if !strings.Contains(doc, `{\Huge\bfseries Weekly \#1 \& more}`) {
+		t.Fatal("Expected the escaped title.")
+	}
+	// 2 pages of puzzles and a page of solutions
+	if n := strings.Count(doc, `\newpage`); n != 2 {
+		t.Fatalf("Expected 2 page breaks but got %d.\n", n)
+	}
+	for k, p := range list {
+		label := "Puzzle " + string(rune('1'+k)) + ` \quad ` + p.Rating.Band
+		if !strings.Contains(doc, label) {
+			t.Fatalf("Expected the label %q.\n", label)
+		}
+	}
+
+	// every cell of a solution is printed, the solved ones in grey
+	sols := doc[strings.Index(doc, "Solutions"):]
+	if n := strings.Count(sols, `\node`); n != len(list)*N*N {
+		t.Fatalf("Expected %d digits in the solutions but got %d.\n", len(list)*N*N, n)
+	}
+	empty := 0
+	for _, p := range list {
+		empty += CountEmpty(p.Puzzle)
+	}
+	if n := strings.Count(sols, `\node[gray]`); n != empty {
+		t.Fatalf("Expected %d solved digits but got %d.\n", empty, n)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	. "github.com/mjwong/sudoku2/lib"
+	. "github.com/mjwong/sudoku2/linkedlist"
+)
+
+// Mistakes in the pencil marks of a grid
+type MarksReport struct {
+	Removed []Elim // digits of the solution missing from the marks of their cell
+	Missing []Elim // candidates in the marks ruled out by the digits placed
+}
+
+// Number of mistakes in the report
+func (r MarksReport) Count() int {
+	return len(r.Removed) + len(r.Missing)
+}
+
+// Check the pencil marks of the grid m against its solution and the candidates left by
+// the digits placed. Cells without marks are not checked. Returns an error if the grid
+// does not have a unique solution.
+func checkPencilMarks(m Intmat, marks Pmat) (MarksReport, error) {
+	var r MarksReport
+
+	sol, cnt := SolveUnique(m)
+	if cnt != 1 {
+		return r, fmt.Errorf("puzzle has %s", SolutionsText(cnt))
+	}
+	_, cands := GetPossibleMat(m)
+
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			if m[i][j] != 0 || len(marks[i][j]) == 0 {
+				continue
+			}
+			if !Contains(marks[i][j], sol[i][j]) {
+				r.Removed = append(r.Removed, Elim{Row: i, Col: j, Dig: sol[i][j]})
+			}
+			for _, d := range marks[i][j] {
+				if !Contains(cands[i][j], d) {
+					r.Missing = append(r.Missing, Elim{Row: i, Col: j, Dig: d})
+				}
+			}
+		}
+	}
+	return r, nil
+}
+
+// Print the mistakes of the report
+func (r MarksReport) Print() {
+	for _, e := range r.Removed {
+		fmt.Printf("%s: %d was removed but is the digit of the solution\n", CellName(e.Row, e.Col), e.Dig)
+	}
+	for _, e := range r.Missing {
+		fmt.Printf("%s<>%d is missing, the digit is ruled out by the digits placed\n", CellName(e.Row, e.Col), e.Dig)
+	}
+}
+
+// Check pencil marks: sudoku2 [variant flags] check-marks -pencil file [file]
+// Prints the mistakes in the marks. Exits with 1 if there are any.
+func runCheckMarks(args []string) {
+	fs := newFlagSet("check-marks")
+	marksFile := fs.String("pencil", "", "Pencil marks file, one field of candidates per cell.")
+	fs.Parse(args)
+
+	if *marksFile == "" {
+		fs.Usage()
+		os.Exit(ExitInvalid)
+	}
+	input, err := readPuzzle(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(ExitInvalid)
+	}
+	marks, err := readPencilMarks(*marksFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", *marksFile, err)
+		os.Exit(ExitInvalid)
+	}
+
+	r, err := checkPencilMarks(PopulateMat(input), marks)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(ExitInvalid)
+	}
+	r.Print()
+	fmt.Printf("Mistakes: %d\n", r.Count())
+	if r.Count() > 0 {
+		os.Exit(ExitUnsolved)
+	}
+}
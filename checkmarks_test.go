@@ -0,0 +1,72 @@
+package main
+
+import (
+	"testing"
+
+	. "github.com/mjwong/sudoku2/lib"
+	. "github.com/mjwong/sudoku2/linkedlist"
+)
+
+func TestCheckPencilMarks(t *testing.T) {
+	input, err := readPuzzle("difficult1.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := PopulateMat(input)
+	sol, _ := SolveUnique(m)
+	_, marks := GetPossibleMat(m)
+
+	r, err := checkPencilMarks(m, marks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Count() != 0 {
+		t.Fatalf("Expected no mistakes but got %+v.\n", r)
+	}
+
+	// remove the solution from one cell and add a digit placed in its row to another
+	var row, col, col2 int
+	for k := 0; k < N*N; k++ {
+		if m[k/N][k%N] == 0 {
+			row, col = k/N, k%N
+			break
+		}
+	}
+	for c := 0; c < N; c++ {
+		if m[row][c] == 0 && c != col {
+			col2 = c
+		}
+	}
+	placed := 0
+	for c := 0; c < N; c++ {
+		if m[row][c] != 0 {
+			placed = m[row][c]
+		}
+	}
+	marks[row][col] = EraseFromSlice(append([]int(nil), marks[row][col]...), sol[row][col])
+	marks[row][col2] = append(append([]int(nil), marks[row][col2]...), placed)
+	if len(marks[row][col]) == 0 {
+		marks[row][col] = []int{placed}
+	}
+
+	r, err = checkPencilMarks(m, marks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Removed) != 1 || r.Removed[0] != (Elim{Row: row, Col: col, Dig: sol[row][col]}) {
+		t.Fatalf("Expected %s removed but got %+v.\n", CellName(row, col), r.Removed)
+	}
+	found := false
+	for _, e := range r.Missing {
+		found = found || e == Elim{Row: row, Col: col2, Dig: placed}
+	}
+	if !found {
+		t.Fatalf("Expected %s<>%d missing but got %+v.\n", CellName(row, col2), placed, r.Missing)
+	}
+
+	// not unique
+	var empty Intmat
+	if _, err := checkPencilMarks(empty, marks); err == nil {
+		t.Fatal("Expected an error for a puzzle with more than one solution.")
+	}
+}
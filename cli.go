@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mjwong/sudoku2/format"
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+// Exit codes of the commands
+const (
+	ExitSolved   = 0 // solved, or the command succeeded
+	ExitUnsolved = 1 // the rules did not solve the puzzle, or the command failed
+	ExitInvalid  = 2 // invalid input or usage, or a puzzle without a solution
+	ExitMultiple = 3 // the puzzle has more than one solution
+)
+
+// Exit code for a count of solutions other than one
+func solutionsExit(cnt int) int {
+	if cnt == 0 {
+		return ExitInvalid
+	}
+	return ExitMultiple
+}
+
+// Print the error and exit with ExitInvalid
+func exitInvalid(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(ExitInvalid)
+}
+
+// The higher of two exit codes, for a command over many puzzles
+func worstExit(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Subcommand of the binary
+type command struct {
+	name  string
+	args  string // synopsis of the flags and arguments
+	about string
+	run   func(args []string)
+}
+
+// Commands in the order of the help. Set in init as the commands refer back to the table
+// for their help.
+var commands []command
+
+func init() {
+	commands = []command{
+		{"solve", "[-r rule | -rules name] [-explain] [-format json] [-i file | -pm file | file]",
+			"Solve puzzles with the rules, the default without a command.", runSolve},
+		{"grade", "[-tag] [file ...]", "Rate the difficulty of puzzles.", runGrade},
+		{"hint", "[-level 1-4] [-pencil file] [file]", "Print the next step of the simplest technique.", runHint},
+		{"generate", "[-n count] [-sym s] [-needs band] ...", "Generate puzzles with a unique solution.", runGenerate},
+		{"validate", "[-from format] [file ...]", "Check that puzzles have exactly one solution.", runValidate},
+		{"bench", "[-n runs] [-r rule | -rules name] [-from format] file", "Time the solve of each puzzle of a file.", runBench},
+		{"batch", "[-j workers] [-from format] [-q] file", "Solve a file of puzzles concurrently with statistics.", runBatch},
+		{"convert", "[-from format] -to format [-o file] file", "Convert puzzle files between formats.", runConvert},
+		{"minimize", "[-sym s] [-seed n] [-report] [file]", "Remove the clues not needed for a unique solution.", runMinimize},
+		{"check-marks", "-pencil file [file]", "Check pencil marks against the solution.", runCheckMarks},
+		{"render", "-o file.svg|file.png [-solve] [-cands] [-hint] [file]", "Draw a puzzle as SVG or PNG.", runRender},
+		{"booklet", "[-title t] [-per n] [-o file.tex] file", "Write a LaTeX booklet of puzzles and solutions.", runBooklet},
+		{"html", "[-o file.html] [-title t] [file]", "Write an HTML walkthrough of the solve.", runHTML},
+	}
+	flag.Usage = printUsage
+}
+
+// Print the usage of the binary with its commands
+func printUsage() {
+	w := flag.CommandLine.Output()
+	fmt.Fprintln(w, "Usage: sudoku2 [flags] [command] [command flags]")
+	fmt.Fprintln(w, "\nCommands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-12s %s\n", c.name, c.about)
+	}
+	fmt.Fprintln(w, "\nRun sudoku2 help <command> for the flags of a command.")
+	fmt.Fprintln(w, "Exit codes: 0 solved, 1 unsolved or failed, 2 invalid input, 3 more than one solution.")
+	fmt.Fprintln(w, "\nFlags, before the command:")
+	flag.PrintDefaults()
+}
+
+// Run the command named by the first argument. Returns false for an unknown command.
+func runCommand(args []string) bool {
+	if args[0] == "help" {
+		if len(args) == 1 {
+			flag.CommandLine.SetOutput(os.Stdout)
+			printUsage()
+			return true
+		}
+		args = []string{args[1], "-h"}
+	}
+	for _, c := range commands {
+		if c.name == args[0] {
+			c.run(args[1:])
+			return true
+		}
+	}
+	return false
+}
+
+// Flags of a command, with the usage of the command as its help
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		w := fs.Output()
+		for _, c := range commands {
+			if c.name == name {
+				fmt.Fprintf(w, "Usage: sudoku2 [flags] %s %s\n%s\n\n", c.name, c.args, c.about)
+			}
+		}
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// Rules of -r by name
+var ruleNames = map[string]int{
+	"guess":   0,   // backtracking with iterMat
+	"open":    1,   // open singles
+	"hidden":  3,   // hidden singles
+	"pairs":   5,   // naked pairs
+	"singles": 13,  // open and hidden singles
+	"basic":   135, // singles and naked pairs
+	"xwing":   20,  // X-wings
+	"all":     99,  // all the rules, then iterMat
+}
+
+// Names of the rules for the help, in the order of their numbers
+func ruleNamesText() string {
+	var list []string
+	for name := range ruleNames {
+		list = append(list, name)
+	}
+	sort.Slice(list, func(a, b int) bool { return ruleNames[list[a]] < ruleNames[list[b]] })
+	for k, name := range list {
+		list[k] = fmt.Sprintf("%s (%d)", name, ruleNames[name])
+	}
+	return strings.Join(list, ", ")
+}
+
+// Set -r from the name of the rules. Returns an error for an unknown name.
+func setRuleName(name string) error {
+	n, ok := ruleNames[name]
+	if !ok {
+		return fmt.Errorf("unknown rules %q, expected one of %s", name, ruleNamesText())
+	}
+	*rule = n
+	return nil
+}
+
+// Check -format and -grid and apply -no-color and -grid
+func applyOutputFlags() error {
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unknown output format %q, expected text or json", *output)
+	}
+	if *gridFmt == GridBox && *marks != "" {
+		return fmt.Errorf("-grid box cannot show the marks between the cells of -marks, use rows or line")
+	}
+	if *noColor || os.Getenv("NO_COLOR") != "" || !IsTerminal(os.Stdout) {
+		SetColor(false)
+	}
+	return SetGridStyle(*gridFmt)
+}
+
+// Validate puzzles: sudoku2 [variant flags] validate [-from format] [file ...]
+// Prints the number of solutions of each puzzle. Exits with the highest code of the
+// puzzles: 0 if all have one solution, 2 for none, 3 for more than one.
+func runValidate(args []string) {
+	fs := newFlagSet("validate")
+	from := fs.String("from", "", "Format of the files: "+strings.Join(format.Names(), ", ")+". Detected if not set.")
+	fs.Parse(args)
+
+	fnames := fs.Args()
+	if len(fnames) == 0 {
+		fnames = []string{""} // stdin
+	}
+
+	code := ExitSolved
+	for _, fname := range fnames {
+		puzzles, err := readPuzzles(fname, *from)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			code = worstExit(code, ExitInvalid)
+			continue
+		}
+
+		for _, p := range puzzles {
+			name := fmt.Sprintf("line %d", p.Line)
+			if fname != "" {
+				name = fmt.Sprintf("%s:%d", fname, p.Line)
+			}
+
+			if cnt := CountSolutions(p.Mat, 2); cnt != 1 {
+				fmt.Printf("%s: %s\n", name, SolutionsText(cnt))
+				code = worstExit(code, solutionsExit(cnt))
+			} else {
+				fmt.Printf("%s: valid, %d clues\n", name, N*N-CountEmpty(p.Mat))
+			}
+		}
+	}
+	os.Exit(code)
+}
+
+// Read the puzzles of the file in the format, or of stdin if fname is empty
+func readPuzzles(fname, name string) ([]Puzzle, error) {
+	if fname != "" {
+		return format.ReadFile(fname, name)
+	}
+	return readStdin(false)
+}
+
+// Read the puzzles of stdin. From a terminal, prompts if asked to and reads a line,
+// otherwise reads to the end.
+func readStdin(prompt bool) ([]Puzzle, error) {
+	var r io.Reader = os.Stdin
+	if IsTerminal(os.Stdin) {
+		if prompt {
+			fmt.Println("Enter sudoku string (. rep empty square)")
+		}
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		r = strings.NewReader(line)
+	}
+
+	list, err := ReadPuzzles(r)
+	if err == nil && len(list) == 0 {
+		err = fmt.Errorf("no puzzle")
+	}
+	return list, err
+}
+
+// Benchmark the solver: sudoku2 [variant flags] bench [-n runs] [-r rule | -rules name] [-from format] file
+// Solves each puzzle of the file n times with the output of the solver suppressed, and
+// prints the fastest and the mean time of each puzzle and of the whole file.
+func runBench(args []string) {
+	s := newSolver()
+
+	fs := newFlagSet("bench")
+	runs := fs.Int("n", 3, "Runs per puzzle.")
+	fs.IntVar(rule, "r", *rule, "Rules by number, see -rules.")
+	rules := fs.String("rules", "", "Rules by name: "+ruleNamesText()+".")
+	from := fs.String("from", "", "Format of the file: "+strings.Join(format.Names(), ", ")+". Detected if not set.")
+	fs.Parse(args)
+
+	if *rules != "" {
+		if err := setRuleName(*rules); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(ExitInvalid)
+		}
+	}
+	if fs.NArg() != 1 || *runs < 1 {
+		fs.Usage()
+		os.Exit(ExitInvalid)
+	}
+	puzzles, err := format.ReadFile(fs.Arg(0), *from)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(ExitInvalid)
+	}
+
+	*output, *explain = "text", false
+	log.SetOutput(io.Discard) // the timing line of solve
+	var total, fastest time.Duration
+	solved := 0
+	for k, p := range puzzles {
+		var sum, best time.Duration
+		ok := false
+		for n := 0; n < *runs; n++ {
+			restore := quiet()
+			start := time.Now()
+			final := s.solve(p.Mat, nil, p.Line)
+			d := time.Since(start)
+			restore()
+
+			sum += d
+			if n == 0 || d < best {
+				best = d
+			}
+			ok = CountEmpty(final) == 0
+		}
+		if ok {
+			solved++
+		}
+		total += sum
+		fastest += best
+		fmt.Printf("Puzzle %d, line %d: best %.3f ms, mean %.3f ms, solved %v\n",
+			k+1, p.Line, millis(best), millis(sum/time.Duration(*runs)), ok)
+	}
+	fmt.Printf("Puzzles: %d. Solved: %d. Rule: %d. Runs: %d.\n", len(puzzles), solved, *rule, *runs)
+	fmt.Printf("Total best %.3f ms, mean %.3f ms per puzzle.\n",
+		millis(fastest), millis(total/time.Duration(*runs*len(puzzles))))
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+func TestSolveExit(t *testing.T) {
+	s := newSolver()
+
+	defer func(r int) { *rule = r }(*rule)
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	expert, err := readPuzzle("expert3.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		rules, puzzle string
+		code          int
+	}{
+		{"guess", expert, ExitSolved},
+		{"all", expert, ExitSolved},
+		{"singles", expert, ExitUnsolved}, // needs more than singles
+		{"guess", "..4.2........873.4...........5.......3....1..........9.42......19....7.....7.3...", ExitMultiple},
+		{"guess", "11...............................................................................", ExitInvalid},
+	}
+	for _, tc := range tests {
+		if err := setRuleName(tc.rules); err != nil {
+			t.Fatal(err)
+		}
+		restore := quiet()
+		code := s.solveExit(PopulateMat(tc.puzzle), nil, 0)
+		restore()
+		if code != tc.code {
+			t.Fatalf("%s %s: expected exit code %d but got %d.\n", tc.rules, tc.puzzle, tc.code, code)
+		}
+	}
+
+	// the candidates of a sukaku make a puzzle with many solutions unique
+	if err := setRuleName("guess"); err != nil {
+		t.Fatal(err)
+	}
+	m := PopulateMat("..4.2........873.4...........5.......3....1..........9.42......19....7.....7.3...")
+	sol, _ := SolveUnique(m)
+	var cands Pmat
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			if m[i][j] == 0 {
+				cands[i][j] = []int{sol[i][j], sol[i][j]%N + 1}
+			}
+		}
+	}
+	if u, cnt := SolveUniqueCands(m, cands); cnt != 1 || u != sol {
+		t.Fatalf("Expected the solution restricted to the candidates but got %d.\n", cnt)
+	}
+	restore := quiet()
+	code := s.solveExit(m, &cands, 0)
+	restore()
+	if code != ExitSolved {
+		t.Fatalf("Expected exit code %d for the candidates but got %d.\n", ExitSolved, code)
+	}
+
+	if err := setRuleName("fish"); err == nil {
+		t.Fatal("Expected an error for unknown rules.")
+	}
+	if worstExit(ExitMultiple, ExitInvalid) != ExitMultiple || worstExit(ExitSolved, ExitUnsolved) != ExitUnsolved {
+		t.Fatal("Expected the higher exit code.")
+	}
+}
+
+func TestCommands(t *testing.T) {
+	names := map[string]bool{}
+	for _, c := range commands {
+		if names[c.name] {
+			t.Fatalf("Command %s is listed twice.\n", c.name)
+		}
+		names[c.name] = true
+	}
+	for _, name := range []string{"solve", "grade", "hint", "generate", "validate", "bench", "convert"} {
+		if !names[name] {
+			t.Fatalf("Expected the command %s.\n", name)
+		}
+	}
+	if runCommand([]string{"fish"}) {
+		t.Fatal("Expected false for an unknown command.")
+	}
+}
+
+func TestReadStdin(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(f *os.File) { os.Stdin = f }(os.Stdin)
+	os.Stdin = r
+
+	spaced := strings.Join(strings.Split("...15....91..764..5.6.4.3........69.6..5.4..7.71........7.3.9.6..386..15....95...", ""), " ")
+	w.WriteString(spaced + "\n# a comment\n..4.2........873.4...........5.......3....1..........9.42......19....7.....7.3...\n")
+	w.Close()
+
+	list, err := readStdin(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Line != 1 || list[1].Line != 3 || list[0].Mat[0][3] != 1 {
+		t.Fatalf("Expected 2 puzzles on lines 1 and 3 but got %v.\n", list)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mjwong/sudoku2/format"
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+// Convert puzzle files: sudoku2 convert [-from sdk] -to ss [-o file] file
+// The format of the file is detected if -from is not set. Writes to stdout without -o.
+func runConvert(args []string) {
+	names := strings.Join(format.Names(), ", ")
+	fs := newFlagSet("convert")
+	from := fs.String("from", "", "Format of the file: "+names+". Detected if not set.")
+	to := fs.String("to", "line", "Format to write: "+names+".")
+	out := fs.String("o", "", "Output file, stdout if not set.")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "convert: expected a puzzle file")
+		os.Exit(ExitInvalid)
+	}
+	puzzles, err := format.ReadFile(fs.Arg(0), *from)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(ExitInvalid)
+	}
+
+	var list []Intmat
+	for _, p := range puzzles {
+		list = append(list, p.Mat)
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		if w, err = os.Create(*out); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(ExitUnsolved)
+		}
+		defer w.Close()
+	}
+	if err := format.Write(w, *to, list); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(ExitUnsolved)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	. "github.com/mjwong/sudoku2/lib"
+	. "github.com/mjwong/sudoku2/linkedlist"
+	"github.com/mjwong/sudoku2/render"
+)
+
+// Draw a puzzle: sudoku2 [variant flags] render -o file.svg [-size 60] [-solve] [-cands] [-hint] [file]
+// Writes an SVG or PNG image by the extension of the output file.
+func runRender(args []string) {
+	fs := newFlagSet("render")
+	out := fs.String("o", "", "Output file, .svg or .png.")
+	size := fs.Int("size", 60, "Pixels per cell.")
+	solve := fs.Bool("solve", false, "Draw the solution, the solved digits in another colour.")
+	cands := fs.Bool("cands", false, "Draw the candidates of the empty cells.")
+	hint := fs.Bool("hint", false, "Draw the candidates and highlight the next step of the rules.")
+	fs.Parse(args)
+
+	if *out == "" {
+		fmt.Fprintln(os.Stderr, "render: expected an output file with -o")
+		os.Exit(ExitInvalid)
+	}
+	if err := render.CheckFile(*out); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(ExitInvalid)
+	}
+	input, err := readPuzzle(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(ExitInvalid)
+	}
+
+	m := PopulateMat(input)
+	opts := render.Options{CellSize: *size, Givens: &m}
+	grid := m
+
+	if *cands || *hint {
+		_, pm := GetPossibleMat(m)
+		opts.Cands = &pm
+	}
+	if *hint {
+		if e, ok := nextStep(m, nil); ok {
+			opts.Step = &e
+		}
+	}
+	if *solve {
+		sol, cnt := SolveUnique(m)
+		if cnt != 1 {
+			fmt.Fprintf(os.Stderr, "puzzle has %s\n", SolutionsText(cnt))
+			os.Exit(solutionsExit(cnt))
+		}
+		grid = sol
+	}
+
+	if err := render.WriteFile(*out, grid, opts); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(ExitUnsolved)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+
+	. "github.com/mjwong/sudoku2/lib"
+	. "github.com/mjwong/sudoku2/linkedlist"
+)
+
+// Start a new step of the explanation. Placements and eliminations which follow are
+// recorded in it.
+func (s *solver) beginEvent(e Event) {
+	if s.explaining {
+		s.events = append(s.events, e)
+	}
+}
+
+// Place the digit in the cell of the node. All placements of the rules go through here
+// and are recorded in the history.
+func (s *solver) placeDigit(node *Cell, dig int) {
+	row, col := node.Row, node.Col
+	s.record(operation{place: true, row: row, col: col, dig: dig, vals: append([]int(nil), s.mat2[row][col]...)})
+	s.emptyL.DelNode(node) // remove current Node from possibility list
+	s.mat[row][col] = dig
+	s.mat2[row][col] = nil
+	s.emptyCnt--
+
+	if s.explaining && len(s.events) > 0 {
+		e := &s.events[len(s.events)-1]
+		e.Placements = append(e.Placements, Placement{Row: row, Col: col, Dig: dig})
+	}
+}
+
+// Erase the candidate from the cell. All eliminations of the rules go through
+// here and are recorded in the history.
+func (s *solver) eraseCandidate(row, col, dig int) {
+	s.record(operation{row: row, col: col, dig: dig})
+	s.mat2[row][col] = EraseFromSlice(s.mat2[row][col], dig)
+	// remove this digit from cell at this position of the empty list
+	s.emptyL.EraseDigitFromCell(row, col, dig)
+
+	if s.explaining && len(s.events) > 0 {
+		e := &s.events[len(s.events)-1]
+		e.Elims = append(e.Elims, Elim{Row: row, Col: col, Dig: dig})
+	}
+}
+
+// The events recorded which placed a digit or erased a candidate
+func (s *solver) explained() []Event {
+	var list []Event
+	for _, e := range s.events {
+		if len(e.Placements) > 0 || len(e.Elims) > 0 {
+			list = append(list, e)
+		}
+	}
+	return list
+}
+
+// House of a hidden single, the box first
+func hiddenHouse(row, col int, notInRow, notInCol, notInBlk bool, house int) string {
+	switch {
+	case notInBlk:
+		return BoxName(BlkOf(row, col))
+	case notInRow:
+		return RowName(row)
+	case notInCol:
+		return ColName(col)
+	default:
+		return HouseName(house)
+	}
+}
+
+// Print the steps of the solve in the standard notation
+func (s *solver) printExplained() {
+	for k, e := range s.explained() {
+		fmt.Printf("%3d. %v\n", k+1, e)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"testing"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+func TestEventString(t *testing.T) {
+	tests := []struct {
+		e        Event
+		expected string
+	}{
+		{Event{Technique: TechHiddenSingle, House: BoxName(1), Placements: []Placement{{Row: 2, Col: 4, Dig: 7}}},
+			"r3c5=7 (hidden single in box 2)"},
+		{Event{Technique: TechNakedSingle, Placements: []Placement{{Row: 0, Col: 0, Dig: 1}}},
+			"r1c1=1 (naked single)"},
+		{Event{Technique: TechNakedPair, Digits: []int{3, 7}, House: RowName(1),
+			Cells: []Coord{{Row: 1, Col: 0}, {Row: 1, Col: 4}},
+			Elims: []Elim{{Row: 1, Col: 2, Dig: 3}, {Row: 1, Col: 6, Dig: 7}}},
+			"naked pair 3,7 in row 2 r2c1 r2c5 => r2c3<>3, r2c7<>7"},
+		{Event{Technique: TechXWing, Digits: []int{4},
+			Cells: []Coord{{Row: 6, Col: 7}, {Row: 1, Col: 2}, {Row: 1, Col: 7}, {Row: 6, Col: 2}},
+			Elims: []Elim{{Row: 4, Col: 2, Dig: 4}}},
+			"x-wing 4 r2r7 c3c8 => r5c3<>4"},
+	}
+
+	for _, tc := range tests {
+		if s := tc.e.String(); s != tc.expected {
+			t.Fatalf("Expected %q but got %q.\n", tc.expected, s)
+		}
+	}
+}
+
+func TestExplainSolve(t *testing.T) {
+	s := newSolver()
+
+	tests := []struct {
+		fname string
+		tech  string // hardest technique explained
+	}{
+		{"difficult1.txt", TechHiddenSingle},
+		{"difficult3.txt", TechNakedPair},
+		{"difficult5.txt", TechXWing},
+	}
+
+	for _, tc := range tests {
+		input, err := readPuzzle(tc.fname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sol, _ := SolveUnique(PopulateMat(input))
+
+		prof := s.solveLogic(input)
+		placed, found := 0, false
+		for _, e := range prof.Events {
+			found = found || e.Technique == tc.tech
+			for _, p := range e.Placements {
+				if sol[p.Row][p.Col] != p.Dig {
+					t.Fatalf("%s: %v places a wrong digit.\n", tc.fname, e)
+				}
+				placed++
+			}
+			for _, el := range e.Elims {
+				if sol[el.Row][el.Col] == el.Dig {
+					t.Fatalf("%s: %v erases a digit of the solution.\n", tc.fname, e)
+				}
+			}
+		}
+
+		if !found {
+			t.Fatalf("%s: expected a step with %s.\n", tc.fname, tc.tech)
+		}
+		if empty := CountEmpty(PopulateMat(input)); placed != empty {
+			t.Fatalf("%s: expected %d placements but got %d.\n", tc.fname, empty, placed)
+		}
+	}
+
+	if s.explaining {
+		t.Fatal("Expected explaining to be off after the solve.")
+	}
+}
@@ -0,0 +1,232 @@
+package format
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+// Candidates of a solving session or of a candidate-only puzzle, in two formats:
+//
+// A candidate grid as written by HoDoKu or SudokuWiki, with the candidates of each cell
+// in a column and lines of dashes around the boxes. A placed digit is marked with +,
+// so a cell with one candidate is not taken for a placed one:
+//
+//	.----------------.----------------.----------------.
+//	| 4    19   289  | 289  +6   +3   | 5    7    128  |
+//
+// A Sukaku string of 729 characters, 9 per cell in row order. Character k of a cell is
+// the digit k+1 if it is a candidate, otherwise 0 or a dot. Sukaku has no placed digits:
+// a placed cell is written as its only candidate and read back as such.
+//
+// The placed cells are their digit in the Intmat and nil in the Pmat, the other cells
+// are 0 in the Intmat with their candidates in the Pmat.
+
+const sukakuLen = N * N * N
+
+// Read candidates in either format
+func ReadCandidates(s string) (Intmat, Pmat, error) {
+	compact := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+	if len(compact) == sukakuLen && strings.Trim(compact, "0123456789.") == "" {
+		return ParseSukaku(compact)
+	}
+	return ParseCandidateGrid(s)
+}
+
+// Read the candidates of the file in either format
+func ReadCandidatesFile(fname string) (Intmat, Pmat, error) {
+	b, err := os.ReadFile(fname)
+	if err != nil {
+		return Intmat{}, Pmat{}, err
+	}
+
+	m, pm, err := ReadCandidates(string(b))
+	if err != nil {
+		return m, pm, fmt.Errorf("%s: %v", fname, err)
+	}
+	return m, pm, nil
+}
+
+// Write the candidates to the file, as a Sukaku string if the file ends in .sukaku and
+// as a candidate grid otherwise
+func WriteCandidatesFile(fname string, m Intmat, pm Pmat) error {
+	f, err := os.Create(fname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if strings.ToLower(filepath.Ext(fname)) == ".sukaku" {
+		err = WriteSukaku(f, m, pm)
+	} else {
+		err = WriteCandidateGrid(f, m, pm)
+	}
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// Parse a Sukaku string of 729 characters
+func ParseSukaku(s string) (Intmat, Pmat, error) {
+	var cands Pmat
+
+	if len(s) != sukakuLen {
+		return Intmat{}, cands, fmt.Errorf("expected %d characters but got %d", sukakuLen, len(s))
+	}
+	for k := 0; k < sukakuLen; k++ {
+		cell, dig := k/N, k%N+1
+		switch ch := s[k]; {
+		case ch == '0' || ch == '.':
+		case int(ch-'0') == dig:
+			cands[cell/N][cell%N] = append(cands[cell/N][cell%N], dig)
+		default:
+			return Intmat{}, cands, fmt.Errorf("cell %s: expected %d, 0 or . but got %q", CellName(cell/N, cell%N), dig, ch)
+		}
+	}
+
+	return Intmat{}, cands, checkCandidates(Intmat{}, cands)
+}
+
+// Parse a candidate grid. Lines of dashes are skipped, the other lines are the rows of
+// 9 cells separated by white space or |. A cell is its candidates, or + and its digit
+// if placed.
+func ParseCandidateGrid(s string) (Intmat, Pmat, error) {
+	var (
+		m     Intmat
+		cands Pmat
+		row   int
+	)
+
+	for n, l := range strings.Split(s, "\n") {
+		if strings.Trim(l, ".-:+'*| \t\r") == "" {
+			continue
+		}
+		if row == N {
+			return Intmat{}, cands, fmt.Errorf("line %d: expected %d rows", n+1, N)
+		}
+
+		fields := strings.Fields(strings.ReplaceAll(l, "|", " "))
+		if len(fields) != N {
+			return Intmat{}, cands, fmt.Errorf("line %d: expected %d cells but got %d", n+1, N, len(fields))
+		}
+		for col, f := range fields {
+			if len(f) == 2 && f[0] == '+' && f[1] >= '1' && f[1] <= '0'+N {
+				m[row][col] = int(f[1] - '0')
+				continue
+			}
+			for _, ch := range f {
+				d := int(ch - '0')
+				if d < 1 || d > N || Contains(cands[row][col], d) {
+					return Intmat{}, cands, fmt.Errorf("line %d: bad candidates %q in %s", n+1, f, CellName(row, col))
+				}
+				cands[row][col] = append(cands[row][col], d)
+			}
+		}
+		row++
+	}
+	if row != N {
+		return Intmat{}, cands, fmt.Errorf("expected %d rows but got %d", N, row)
+	}
+
+	return m, cands, checkCandidates(m, cands)
+}
+
+// Returns an error for an empty cell without candidates
+func checkCandidates(m Intmat, cands Pmat) error {
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			if m[i][j] == 0 && len(cands[i][j]) == 0 {
+				return fmt.Errorf("cell %s has no candidates", CellName(i, j))
+			}
+		}
+	}
+	return nil
+}
+
+// Candidates of a cell, or its digit marked with + if placed
+func cellText(m Intmat, pm Pmat, i, j int) string {
+	if m[i][j] != 0 {
+		return fmt.Sprintf("+%d", m[i][j])
+	}
+	var s string
+	for _, d := range pm[i][j] {
+		s += fmt.Sprint(d)
+	}
+	return s
+}
+
+// Write the Sukaku string of 729 characters on a line
+func WriteSukaku(w io.Writer, m Intmat, pm Pmat) error {
+	var b strings.Builder
+
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			for d := 1; d <= N; d++ {
+				if m[i][j] == d || m[i][j] == 0 && Contains(pm[i][j], d) {
+					b.WriteByte(byte('0' + d))
+				} else {
+					b.WriteByte('0')
+				}
+			}
+		}
+	}
+	_, err := fmt.Fprintln(w, b.String())
+	return err
+}
+
+// Write the candidate grid, each column as wide as its widest cell
+func WriteCandidateGrid(w io.Writer, m Intmat, pm Pmat) error {
+	var width [N]int
+	for j := 0; j < N; j++ {
+		for i := 0; i < N; i++ {
+			if l := len(cellText(m, pm, i, j)); l > width[j] {
+				width[j] = l
+			}
+		}
+	}
+
+	// the border of a box is as wide as its cells with a space before and after each
+	border := func(ends, mid string) string {
+		s := ends[:1]
+		for b := 0; b < SQ; b++ {
+			l := 1
+			for j := b * SQ; j < (b+1)*SQ; j++ {
+				l += width[j] + 1
+			}
+			if b > 0 {
+				s += mid
+			}
+			s += strings.Repeat("-", l)
+		}
+		return s + ends[1:]
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(border("..", ".") + "\n")
+	for i := 0; i < N; i++ {
+		if i > 0 && i%SQ == 0 {
+			bw.WriteString(border("::", "+") + "\n")
+		}
+		for j := 0; j < N; j++ {
+			if j%SQ == 0 {
+				bw.WriteString("| ")
+			}
+			fmt.Fprintf(bw, "%-*s ", width[j], cellText(m, pm, i, j))
+		}
+		bw.WriteString("|\n")
+	}
+	bw.WriteString(border("''", "'") + "\n")
+	return bw.Flush()
+}
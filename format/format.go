@@ -0,0 +1,127 @@
+// Package format reads and writes puzzle files in the common formats, e.g. SadMan .sdk,
+// Simple Sudoku .ss, .sdm collections, OpenSudoku XML and plain 9x9 grids.
+package format
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+// Reader parses the puzzles of a file
+type Reader func(s string) ([]Puzzle, error)
+
+// Writer writes puzzles in a format
+type Writer func(w io.Writer, list []Intmat) error
+
+// Format of puzzle files
+type Format struct {
+	Name   string
+	Exts   []string          // file extensions, e.g. ".sdk"
+	Detect func(string) bool // true if the content looks like this format
+	Read   Reader
+	Write  Writer
+}
+
+var (
+	formats = map[string]Format{}
+	detects []string // names of the formats in the order of detection
+)
+
+// Add a format, or replace the one of the same name. Formats are detected from the
+// content in the order they were registered.
+func Register(f Format) {
+	if _, ok := formats[f.Name]; !ok {
+		detects = append(detects, f.Name)
+	}
+	formats[f.Name] = f
+}
+
+func init() {
+	Register(Format{Name: "opensudoku", Exts: []string{".opensudoku", ".xml"}, Detect: isOpenSudoku, Read: readOpenSudoku, Write: writeOpenSudoku})
+	Register(Format{Name: "ss", Exts: []string{".ss"}, Detect: isSimpleSudoku, Read: readSimpleSudoku, Write: writeSimpleSudoku})
+	Register(Format{Name: "sdk", Exts: []string{".sdk"}, Detect: isSadMan, Read: readSadMan, Write: writeSadMan})
+	Register(Format{Name: "sdm", Exts: []string{".sdm"}, Detect: isSdm, Read: readLines, Write: writeSdm})
+	Register(Format{Name: "line", Exts: []string{".txt"}, Detect: isLines, Read: readLines, Write: writeLines})
+	Register(Format{Name: "grid", Exts: []string{".grid"}, Detect: isGrid, Read: readGrids, Write: writeGrids})
+}
+
+// Names of the formats, sorted
+func Names() []string {
+	var list []string
+	for name := range formats {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// Format of the name
+func Get(name string) (Format, error) {
+	f, ok := formats[strings.ToLower(name)]
+	if !ok {
+		return f, fmt.Errorf("unknown format %q, expected one of %s", name, strings.Join(Names(), ", "))
+	}
+	return f, nil
+}
+
+// Format of a file from its content, or from its extension if the content does not tell
+func Detect(fname, s string) (Format, error) {
+	for _, name := range detects {
+		if f := formats[name]; f.Detect != nil && f.Detect(s) {
+			return f, nil
+		}
+	}
+
+	ext := strings.ToLower(filepath.Ext(fname))
+	for _, name := range detects {
+		for _, e := range formats[name].Exts {
+			if e == ext {
+				return formats[name], nil
+			}
+		}
+	}
+	return Format{}, fmt.Errorf("%s: unknown puzzle format", fname)
+}
+
+// Read the puzzles of the file in the format of the name, or the detected one if the
+// name is empty
+func ReadFile(fname, name string) ([]Puzzle, error) {
+	b, err := os.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+
+	var f Format
+	if name == "" {
+		f, err = Detect(fname, string(b))
+	} else {
+		f, err = Get(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := f.Read(string(b))
+	if err != nil {
+		return list, fmt.Errorf("%s: %v", fname, err)
+	}
+	if len(list) == 0 {
+		return list, fmt.Errorf("%s: no puzzle", fname)
+	}
+	return list, nil
+}
+
+// Write the puzzles in the format of the name
+func Write(w io.Writer, name string, list []Intmat) error {
+	f, err := Get(name)
+	if err != nil {
+		return err
+	}
+	return f.Write(w, list)
+}
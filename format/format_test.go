@@ -0,0 +1,201 @@
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	. "github.com/mjwong/sudoku2/lib"
+	. "github.com/mjwong/sudoku2/linkedlist"
+)
+
+const (
+	puzzle1 = "...15....91..764..5.6.4.3........69.6..5.4..7.71........7.3.9.6..386..15....95..."
+	puzzle2 = "14...3.......4...38.3.52.......2..977.6.9.4.545..6.......43.1.29...8.......6...39"
+)
+
+func TestRoundTrip(t *testing.T) {
+	list := []Intmat{PopulateMat(puzzle1), PopulateMat(puzzle2)}
+
+	for _, name := range Names() {
+		in := list
+		if name == "sdk" {
+			in = list[:1]
+		}
+
+		var b bytes.Buffer
+		if err := Write(&b, name, in); err != nil {
+			t.Fatalf("%s: %v\n", name, err)
+		}
+
+		f, err := Detect("puzzle."+name, b.String())
+		if err != nil {
+			t.Fatalf("%s: %v\n", name, err)
+		}
+		if f.Name != name {
+			t.Fatalf("Expected %s to be detected but got %s.\n", name, f.Name)
+		}
+
+		out, err := f.Read(b.String())
+		if err != nil {
+			t.Fatalf("%s: %v\n", name, err)
+		}
+		if len(out) != len(in) {
+			t.Fatalf("%s: expected %d puzzles but got %d.\n", name, len(in), len(out))
+		}
+		for k := range in {
+			if out[k].Mat != in[k] {
+				t.Fatalf("%s: expected %s but got %s.\n", name, MatToString(in[k]), MatToString(out[k].Mat))
+			}
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name, s string
+		line    int // line of the first puzzle
+	}{
+		{"sdk", "#ASadMan\n#DA puzzle\n[Puzzle]\n" + rows(puzzle1, "\n") + "[State]\n123456789\n", 4},
+		{"ss", "*-----------*\n" + strings.ReplaceAll(boxed(puzzle1), ".", "X") + "*-----------*\n", 2},
+		{"grid", "# grid\n\n" + strings.ReplaceAll(rows(puzzle1, "\n"), "", " "), 3},
+		{"line", "# lines\n" + puzzle1 + "\n", 2},
+		{"line", "#Hard\n# Rating: ER 1.5\n" + puzzle1 + "\n", 3},
+		{"line", puzzle1 + "\n# Rating: ER 1.5/EP 1.5 | Easy\n", 1},
+		{"sdm", strings.ReplaceAll(puzzle1, ".", "0") + "\n", 1},
+	}
+
+	for _, tc := range tests {
+		f, err := Detect("", tc.s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Name != tc.name {
+			t.Fatalf("Expected %s but got %s.\n", tc.name, f.Name)
+		}
+
+		list, err := f.Read(tc.s)
+		if err != nil {
+			t.Fatalf("%s: %v\n", tc.name, err)
+		}
+		if len(list) != 1 || MatToString(list[0].Mat) != puzzle1 || list[0].Line != tc.line {
+			t.Fatalf("%s: expected %s on line %d but got %+v.\n", tc.name, puzzle1, tc.line, list)
+		}
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name, s, err string
+	}{
+		{"grid", rows(puzzle1, "\n")[:88], "line 9: expected 9 cells but got 8"},
+		{"grid", strings.Replace(rows(puzzle1, "\n"), "91", "9a", 1), "line 2: bad character 'a'"},
+		{"grid", rows(puzzle1, "\n") + "123456789\n", "line 10: expected 9 rows but got 1"},
+		{"sdk", "#A\n" + rows(puzzle1, "\n") + rows(puzzle2, "\n"), "expected 1 puzzle but got 2"},
+		{"opensudoku", `<opensudoku><game data="123"/></opensudoku>`, "game 1: expected 81 cells but got 3"},
+	}
+
+	for _, tc := range tests {
+		f, err := Get(tc.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Read(tc.s); err == nil || err.Error() != tc.err {
+			t.Fatalf("%s: expected %q but got %v.\n", tc.name, tc.err, err)
+		}
+	}
+
+	if _, err := Get("pdf"); err == nil {
+		t.Fatal("Expected an error for an unknown format.")
+	}
+	// a candidate grid is not Simple Sudoku, nor tags without a grid SadMan
+	if f, err := Detect("", "| 4 19 289 | 289 6 3 | 5 7 128 |\n"); err == nil {
+		t.Fatalf("Expected a candidate grid not to be detected but got %s.\n", f.Name)
+	}
+	if f, err := Detect("", "#A author\n#D description\n"); err == nil {
+		t.Fatalf("Expected tags without a grid not to be detected but got %s.\n", f.Name)
+	}
+	if _, err := Detect("puzzle.pdf", "hello"); err == nil {
+		t.Fatal("Expected an error for an unknown file.")
+	}
+}
+
+// The puzzle in rows of 9 cells, each followed by sep
+func rows(s, sep string) string {
+	var out string
+	for i := 0; i < N; i++ {
+		out += s[i*N:(i+1)*N] + sep
+	}
+	return out
+}
+
+// The puzzle in Simple Sudoku rows like |...|15.|...| with dashes between the bands
+func boxed(s string) string {
+	var out string
+	for i := 0; i < N; i++ {
+		if i == 3 || i == 6 {
+			out += "|---+---+---|\n"
+		}
+		row := s[i*N : (i+1)*N]
+		out += "|" + row[:3] + "|" + row[3:6] + "|" + row[6:] + "|\n"
+	}
+	return out
+}
+
+func TestCandidates(t *testing.T) {
+	m := PopulateMat(puzzle1)
+	_, pm := GetPossibleMat(m)
+	pm[0][0] = EraseFromSlice(append([]int(nil), pm[0][0]...), pm[0][0][0])
+	pm[0][1] = pm[0][1][:1] // a naked single is not placed
+
+	// Sukaku has no placed digits, they come back as cells with one candidate
+	sukakuPm := pm
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			if m[i][j] != 0 {
+				sukakuPm[i][j] = []int{m[i][j]}
+			}
+		}
+	}
+
+	tests := []struct {
+		write func(io.Writer, Intmat, Pmat) error
+		m     Intmat
+		pm    Pmat
+	}{
+		{WriteCandidateGrid, m, pm},
+		{WriteSukaku, Intmat{}, sukakuPm},
+	}
+	for _, tc := range tests {
+		var b bytes.Buffer
+		if err := tc.write(&b, m, pm); err != nil {
+			t.Fatal(err)
+		}
+
+		m2, pm2, err := ReadCandidates(b.String())
+		if err != nil {
+			t.Fatalf("%v\n%s", err, b.String())
+		}
+		if m2 != tc.m || fmt.Sprint(pm2) != fmt.Sprint(tc.pm) {
+			t.Fatalf("Expected the candidates back but got\n%s", b.String())
+		}
+	}
+
+	bad := []struct {
+		s, err string
+	}{
+		{strings.Repeat("0", 729), "cell r1c1 has no candidates"},
+		{"2" + strings.Repeat("0", 728), "cell r1c1: expected 1, 0 or . but got '2'"},
+		{strings.Repeat("1 2 3 4 5 6 7 8 9\n", 8), "expected 9 rows but got 8"},
+		{"1 2 3 4 5 6 7 8\n", "line 1: expected 9 cells but got 8"},
+		{"| 1 2 3 | 4 5 6 | 7 8 0a |\n", `line 1: bad candidates "0a" in r1c9`},
+		{"| 1 2 3 | 4 5 6 | 7 8 +12 |\n", `line 1: bad candidates "+12" in r1c9`},
+	}
+	for _, tc := range bad {
+		if _, _, err := ReadCandidates(tc.s); err == nil || err.Error() != tc.err {
+			t.Fatalf("Expected %q but got %v.\n", tc.err, err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package format
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+// OpenSudoku XML: a game element per puzzle with its 81 digits in the data attribute,
+// 0 for an empty cell
+type openSudoku struct {
+	XMLName xml.Name   `xml:"opensudoku"`
+	Name    string     `xml:"name,omitempty"`
+	Author  string     `xml:"author,omitempty"`
+	Games   []openGame `xml:"game"`
+}
+
+type openGame struct {
+	Data string `xml:"data,attr"`
+}
+
+func isOpenSudoku(s string) bool {
+	return strings.Contains(s, "<opensudoku")
+}
+
+func readOpenSudoku(s string) ([]Puzzle, error) {
+	var (
+		doc  openSudoku
+		list []Puzzle
+	)
+
+	if err := xml.Unmarshal([]byte(s), &doc); err != nil {
+		return nil, err
+	}
+	for k, g := range doc.Games {
+		m, err := ParseMat(g.Data)
+		if err != nil {
+			return list, fmt.Errorf("game %d: %v", k+1, err)
+		}
+		list = append(list, Puzzle{Line: k + 1, Mat: m}) // the number of the game for the line
+	}
+	return list, nil
+}
+
+func writeOpenSudoku(w io.Writer, list []Intmat) error {
+	doc := openSudoku{Name: "sudoku2", Author: "sudoku2"}
+	for _, m := range list {
+		doc.Games = append(doc.Games, openGame{strings.ReplaceAll(MatToString(m), ".", "0")})
+	}
+
+	b, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s%s\n", xml.Header, b)
+	return err
+}
@@ -0,0 +1,271 @@
+package format
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+// Line of a file with its number
+type line struct {
+	n int
+	s string
+}
+
+// Lines of the content without comments from #, white space and blank lines
+func contentLines(s string) []line {
+	var list []line
+
+	for k, l := range strings.Split(s, "\n") {
+		if i := strings.Index(l, "#"); i >= 0 {
+			l = l[:i]
+		}
+		l = strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+			return r
+		}, l)
+		if l != "" {
+			list = append(list, line{k + 1, l})
+		}
+	}
+	return list
+}
+
+// 81 character lines, one puzzle per line
+func isLines(s string) bool {
+	list := contentLines(s)
+	for _, l := range list {
+		if _, err := ParseMat(l.s); err != nil {
+			return false
+		}
+	}
+	return len(list) > 0
+}
+
+func readLines(s string) ([]Puzzle, error) {
+	return ReadPuzzles(strings.NewReader(s))
+}
+
+func writeLines(w io.Writer, list []Intmat) error {
+	for _, m := range list {
+		if _, err := fmt.Fprintln(w, MatToString(m)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// .sdm collections: 81 digits per line, 0 for an empty cell, without comments
+func isSdm(s string) bool {
+	n := 0
+	for _, l := range strings.Split(s, "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" {
+			continue
+		}
+		if len(l) != N*N || strings.Trim(l, "0123456789") != "" {
+			return false
+		}
+		n++
+	}
+	return n > 0
+}
+
+func writeSdm(w io.Writer, list []Intmat) error {
+	for _, m := range list {
+		if _, err := fmt.Fprintln(w, strings.ReplaceAll(MatToString(m), ".", "0")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Plain 9x9 grids: a row of 9 cells per line, blank lines between the puzzles
+func isGrid(s string) bool {
+	list := contentLines(s)
+	for _, l := range list {
+		if len(l.s) != N {
+			return false
+		}
+	}
+	return len(list) > 0 && len(list)%N == 0
+}
+
+func readGrids(s string) ([]Puzzle, error) {
+	return readRows(contentLines(s))
+}
+
+// Read the puzzles of the rows, 9 rows per puzzle
+func readRows(rows []line) ([]Puzzle, error) {
+	var list []Puzzle
+
+	for k := 0; k < len(rows); k += N {
+		if k+N > len(rows) {
+			return list, fmt.Errorf("line %d: expected %d rows but got %d", rows[k].n, N, len(rows)-k)
+		}
+
+		var s string
+		for _, r := range rows[k : k+N] {
+			if len(r.s) != N {
+				return list, fmt.Errorf("line %d: expected %d cells but got %d", r.n, N, len(r.s))
+			}
+			if i := strings.IndexFunc(r.s, badCell); i >= 0 {
+				return list, fmt.Errorf("line %d: bad character %q", r.n, r.s[i])
+			}
+			s += r.s
+		}
+
+		m, err := ParseMat(s)
+		if err != nil {
+			return list, fmt.Errorf("line %d: %v", rows[k].n, err)
+		}
+		list = append(list, Puzzle{Line: rows[k].n, Mat: m})
+	}
+	return list, nil
+}
+
+// True if the character is neither a digit nor an empty cell
+func badCell(r rune) bool {
+	return !strings.ContainsRune("0123456789._*", r)
+}
+
+func writeGrids(w io.Writer, list []Intmat) error {
+	b := bufio.NewWriter(w)
+	for k, m := range list {
+		if k > 0 {
+			b.WriteString("\n")
+		}
+		writeRows(b, m, "", "")
+	}
+	return b.Flush()
+}
+
+// Write the rows of the grid with the separators of cols and of bands every 3 cells
+func writeRows(w io.Writer, m Intmat, colSep, bandSep string) {
+	s := MatToString(m)
+	for i := 0; i < N; i++ {
+		if i > 0 && i%SQ == 0 && bandSep != "" {
+			fmt.Fprintln(w, bandSep)
+		}
+		row := s[i*N : (i+1)*N]
+		var parts []string
+		for j := 0; j < N; j += SQ {
+			parts = append(parts, row[j:j+SQ])
+		}
+		fmt.Fprintln(w, strings.Join(parts, colSep))
+	}
+}
+
+// SadMan Software .sdk: header lines like #A author or #D description, then the 9 rows
+// of one puzzle. Some files have a [Puzzle] section before the rows, and other sections
+// after them.
+func isSadMan(s string) bool {
+	tagged, rows := false, 0
+	for _, l := range strings.Split(s, "\n") {
+		l = strings.TrimSpace(l)
+		switch {
+		case l == "":
+		case rows == 0 && (l == "[Puzzle]" || len(l) > 1 && l[0] == '#' && l[1] >= 'A' && l[1] <= 'Z'):
+			tagged = true
+		case rows == N && strings.HasPrefix(l, "["):
+			return tagged
+		case rows < N && len(l) == N && strings.IndexFunc(l, badCell) < 0:
+			rows++
+		default:
+			return false
+		}
+	}
+	return tagged && rows == N
+}
+
+func readSadMan(s string) ([]Puzzle, error) {
+	var rows []line
+
+	for k, l := range strings.Split(s, "\n") {
+		l = strings.TrimSpace(l)
+		switch {
+		case strings.HasPrefix(l, "[") && l != "[Puzzle]":
+			return readRows(rows) // other sections, e.g. [State], follow the puzzle
+		case l == "" || l[0] == '#' || l == "[Puzzle]":
+		default:
+			rows = append(rows, line{k + 1, l})
+		}
+	}
+
+	list, err := readRows(rows)
+	if err == nil && len(list) > 1 {
+		err = fmt.Errorf("expected 1 puzzle but got %d", len(list))
+	}
+	return list, err
+}
+
+func writeSadMan(w io.Writer, list []Intmat) error {
+	if len(list) != 1 {
+		return fmt.Errorf("sdk holds 1 puzzle but got %d", len(list))
+	}
+	b := bufio.NewWriter(w)
+	b.WriteString("#Asudoku2\n")
+	writeRows(b, list[0], "", "")
+	return b.Flush()
+}
+
+// Simple Sudoku .ss: rows like 1..|...|..9 with lines of dashes between the bands. Empty
+// cells may be X.
+func isSimpleSudoku(s string) bool {
+	rows := 0
+	for _, l := range contentLines(s) {
+		if strings.Trim(l.s, "-+*|") == "" {
+			continue
+		}
+		if !isSimpleRow(l.s) {
+			return false
+		}
+		rows++
+	}
+	return rows > 0 && rows%N == 0
+}
+
+// Row of a .ss file: 3 cells, |, 3 cells, |, 3 cells, with | at the ends in some files
+func isSimpleRow(s string) bool {
+	parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(s, "|"), "|"), "|")
+	if len(parts) != SQ {
+		return false
+	}
+	for _, p := range parts {
+		if len(p) != SQ || strings.IndexFunc(strings.NewReplacer("X", ".", "x", ".").Replace(p), badCell) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func readSimpleSudoku(s string) ([]Puzzle, error) {
+	var rows []line
+
+	for _, l := range contentLines(s) {
+		if strings.Trim(l.s, "-+*|") == "" {
+			continue
+		}
+		l.s = strings.ReplaceAll(l.s, "|", "")
+		l.s = strings.NewReplacer("X", ".", "x", ".").Replace(l.s)
+		rows = append(rows, l)
+	}
+	return readRows(rows)
+}
+
+func writeSimpleSudoku(w io.Writer, list []Intmat) error {
+	b := bufio.NewWriter(w)
+	for k, m := range list {
+		if k > 0 {
+			b.WriteString("\n")
+		}
+		writeRows(b, m, "|", "-----------")
+	}
+	return b.Flush()
+}
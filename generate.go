@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+// Generate puzzles: sudoku2 [variant flags] generate [-sym rot180] [-min 22] [-max 30] [-seed 42] [-n 10] [-needs xwing]
+// Each puzzle is printed on a line in the input format of the solver.
+func runGenerate(args []string) {
+	s := newSolver()
+
+	fs := newFlagSet("generate")
+	sym := fs.String("sym", SymNone, "Symmetry: none, rot180, rot90, diagonal, antidiagonal, horizontal, vertical.")
+	minClues := fs.Int("min", 0, "Minimum number of clues.")
+	maxClues := fs.Int("max", 0, "Maximum number of clues, 0 for no maximum.")
+	seed := fs.Int64("seed", 0, "Random seed for reproducible puzzles, 0 for the current time.")
+	count := fs.Int("n", 1, "Number of puzzles.")
+	tries := fs.Int("tries", 20, "Full grids to try per puzzle to reach the maximum clues.")
+	needs := fs.String("needs", "", "Hardest technique needed: singles, pairs, xwing or guess. Empty for any.")
+	attempts := fs.Int("attempts", 500, "Puzzles to try per puzzle to match -needs.")
+	show := fs.Bool("v", false, "Print the grid and the solution of each puzzle.")
+	fs.Parse(args)
+
+	if *needs != "" {
+		if err := checkBand(*needs); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(ExitInvalid)
+		}
+	}
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	opts := GenOptions{
+		Symmetry: *sym,
+		MinClues: *minClues,
+		MaxClues: *maxClues,
+		MaxTries: *tries,
+		Rand:     rand.New(rand.NewSource(*seed)),
+	}
+
+	for k := 0; k < *count; k++ {
+		puzzle, sol, prof, err := s.generateBand(opts, *needs, *attempts)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(ExitUnsolved)
+		}
+
+		fmt.Println(MatToString(puzzle))
+		if *show {
+			fmt.Printf("Seed: %d. Clues: %d. Symmetry: %s. Needs: %s\n", *seed, N*N-CountEmpty(puzzle), *sym, prof.Band())
+			PrintSudoku(puzzle)
+			PrintSudoku(sol)
+		}
+	}
+}
+
+// Generate puzzles until the logical solver needs the techniques of the band, i.e. its
+// hardest technique is in the band. Any puzzle matches an empty band.
+func (s *solver) generateBand(opts GenOptions, band string, attempts int) (Intmat, Intmat, Profile, error) {
+	for k := 0; k < attempts || k == 0; k++ {
+		puzzle, sol, err := Generate(opts)
+		if err != nil {
+			return puzzle, sol, Profile{}, err
+		}
+
+		prof := s.solveLogic(MatToString(puzzle))
+		if band == "" || prof.Band() == band {
+			return puzzle, sol, prof, nil
+		}
+	}
+	return Intmat{}, Intmat{}, Profile{}, fmt.Errorf("no puzzle needing %s after %d attempts", band, attempts)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+func TestCountSolutions(t *testing.T) {
+	m := PopulateMat("..4.2........873.4...........5.......3....1..........9.42......19....7.....7.3...")
+	if cnt := CountSolutions(m, 2); cnt != 2 {
+		t.Fatalf("Expected 2 solutions of classic sudoku but got %d.\n", cnt)
+	}
+
+	defer ClearVariants()
+	SetVariants("x")
+	if cnt := CountSolutions(m, 2); cnt != 1 {
+		t.Fatalf("Expected 1 solution of sudoku-X but got %d.\n", cnt)
+	}
+
+	m[0][0] = 4
+	if cnt := CountSolutions(m, 2); cnt != 0 {
+		t.Fatalf("Expected no solution but got %d.\n", cnt)
+	}
+}
+
+func TestSymmetricCells(t *testing.T) {
+	tests := []struct {
+		sym  string
+		want int
+	}{
+		{SymNone, 1},
+		{SymRot180, 2},
+		{SymRot90, 4},
+		{SymDiagonal, 2},
+		{SymHorizontal, 2},
+	}
+
+	for _, tc := range tests {
+		if got := SymmetricCells(tc.sym, 0, 1); len(got) != tc.want {
+			t.Fatalf("%s: expected %d cells but got %v.\n", tc.sym, tc.want, got)
+		}
+	}
+
+	// the centre maps onto itself
+	if got := SymmetricCells(SymRot90, 4, 4); len(got) != 1 {
+		t.Fatalf("Expected 1 cell but got %v.\n", got)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	s := newSolver()
+
+	for _, sym := range []string{SymNone, SymRot180, SymRot90, SymDiagonal, SymAntiDiag, SymHorizontal, SymVertical} {
+		opts := GenOptions{Symmetry: sym, MinClues: 24, Rand: rand.New(rand.NewSource(33))}
+		puzzle, sol, err := Generate(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if clues := N*N - CountEmpty(puzzle); clues < 24 {
+			t.Fatalf("%s: expected at least 24 clues but got %d.\n", sym, clues)
+		}
+		if !HasSymmetry(puzzle, sym) {
+			t.Fatalf("%s: expected symmetric clues in %s.\n", sym, MatToString(puzzle))
+		}
+		if CountSolutions(puzzle, 2) != 1 {
+			t.Fatalf("%s: expected a unique solution for %s.\n", sym, MatToString(puzzle))
+		}
+
+		s.PrepPmat(MatToString(puzzle))
+		s.mat3 = s.mat
+		s.iterMat(s.emptyL.Head)
+		if s.mat3 != sol {
+			t.Fatalf("%s: expected %s but got %s.\n", sym, MatToString(sol), MatToString(s.mat3))
+		}
+	}
+}
+
+func TestGenerateSeed(t *testing.T) {
+	opts := GenOptions{MaxClues: 27, Rand: rand.New(rand.NewSource(42))}
+	p1, _, err := Generate(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts.Rand = rand.New(rand.NewSource(42))
+	p2, _, _ := Generate(opts)
+	if p1 != p2 {
+		t.Fatalf("Expected the same puzzle for the same seed but got %s and %s.\n", MatToString(p1), MatToString(p2))
+	}
+	if clues := N*N - CountEmpty(p1); clues > 27 {
+		t.Fatalf("Expected at most 27 clues but got %d.\n", clues)
+	}
+
+	opts.MaxClues, opts.MaxTries = 17, 1
+	if _, _, err := Generate(opts); err == nil {
+		t.Fatal("Expected error for 17 clues.")
+	}
+	if _, _, err := Generate(GenOptions{Symmetry: "spiral"}); err == nil {
+		t.Fatal("Expected error for unknown symmetry.")
+	}
+}
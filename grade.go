@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mjwong/sudoku2/format"
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+// Weight of the step which gives up on the rules and guesses the rest
+const guessWeight = 9.0
+
+// Rating of a puzzle. ER, EP and ED follow Sudoku Explainer: the hardest step, the
+// hardest step up to the first placement, and the first step.
+type Rating struct {
+	ER, EP, ED float64
+	Max        float64 // hardest step, same as ER
+	Sum        float64 // all steps
+	Steps      int
+	Band       string
+}
+
+// Rating bands by ER, easiest first
+var ratingBands = []struct {
+	name string
+	max  float64
+}{
+	{"Easy", 1.5},      // hidden singles
+	{"Medium", 2.3},    // open singles
+	{"Hard", 3.0},      // naked pairs
+	{"Expert", 4.0},    // X-wing
+	{"Diabolical", 99}, // guessing
+}
+
+// Weight of the technique of a rule
+func weightOf(rule int) (float64, bool) {
+	for _, t := range techniques {
+		if t.rule == rule {
+			return t.weight, t.places
+		}
+	}
+	return guessWeight, true
+}
+
+// Rate the profile of a logical solve
+func rate(p Profile) Rating {
+	var r Rating
+
+	steps := append([]int{}, p.Steps...)
+	if !p.Solved {
+		steps = append(steps, 0) // guess
+	}
+
+	placed := false
+	for k, rule := range steps {
+		w, places := weightOf(rule)
+		r.Sum += w
+		if w > r.ER {
+			r.ER = w
+		}
+		if k == 0 {
+			r.ED = w
+		}
+		if !placed && w > r.EP {
+			r.EP = w
+		}
+		placed = placed || places
+	}
+	r.Max = r.ER
+	r.Steps = len(steps)
+
+	for _, b := range ratingBands {
+		if r.ER <= b.max {
+			r.Band = b.name
+			break
+		}
+	}
+	return r
+}
+
+func (r Rating) String() string {
+	return fmt.Sprintf("ER %.1f/EP %.1f/ED %.1f, max %.1f, sum %.1f, %d steps, %s", r.ER, r.EP, r.ED, r.Max, r.Sum, r.Steps, r.Band)
+}
+
+// Grade puzzles: sudoku2 [variant flags] grade [-tag] [file ...]
+// Solves each puzzle of the files with the simplest technique at each step and prints
+// its rating. With -tag the rating of each puzzle is written to its file as a comment
+// line after the puzzle. Only files of one puzzle per line can be tagged.
+func runGrade(args []string) {
+	s := newSolver()
+
+	fs := newFlagSet("grade")
+	tag := fs.Bool("tag", false, "Write the rating into each file.")
+	fs.Parse(args)
+
+	fnames := fs.Args()
+	if len(fnames) == 0 {
+		fnames = []string{""} // stdin
+	}
+
+	for _, fname := range fnames {
+		puzzles, err := readPuzzles(fname, "")
+		if err == nil && *tag && fname != "" {
+			err = checkLineFile(fname)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(ExitInvalid)
+		}
+
+		ratings := make([]Rating, len(puzzles))
+		for k, p := range puzzles {
+			ratings[k] = rate(s.solveLogic(MatToString(p.Mat)))
+			switch {
+			case len(puzzles) > 1:
+				fmt.Printf("%s:%d: %v\n", fname, p.Line, ratings[k])
+			case fname != "":
+				fmt.Printf("%s: %v\n", fname, ratings[k])
+			default:
+				fmt.Println(ratings[k])
+			}
+		}
+
+		if *tag && fname != "" {
+			if err := tagRatings(fname, puzzles, ratings); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(ExitUnsolved)
+			}
+		}
+	}
+}
+
+// Returns an error unless the file has one puzzle per line, which comments can go between
+func checkLineFile(fname string) error {
+	b, err := os.ReadFile(fname)
+	if err != nil {
+		return err
+	}
+	f, err := format.Detect(fname, string(b))
+	if err != nil {
+		return err
+	}
+	if f.Name != "line" {
+		return fmt.Errorf("%s: cannot tag a file of format %s, only one puzzle per line", fname, f.Name)
+	}
+	return nil
+}
+
+// Rewrite the puzzle file with the rating of each puzzle on the line after it, in place
+// of the rating already there. Other lines are left as they are.
+func tagRatings(fname string, puzzles []Puzzle, ratings []Rating) error {
+	b, err := os.ReadFile(fname)
+	if err != nil {
+		return err
+	}
+
+	tags := map[int]string{}
+	for k, p := range puzzles {
+		tags[p.Line] = "# Rating: " + ratings[k].String()
+	}
+
+	var out []string
+	lines := strings.Split(string(b), "\n")
+	for i := 0; i < len(lines); i++ {
+		out = append(out, lines[i])
+		if t, ok := tags[i+1]; ok {
+			out = append(out, t)
+			if i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i+1]), "# Rating:") {
+				i++
+			}
+		}
+	}
+	return os.WriteFile(fname, []byte(strings.Join(out, "\n")), 0644)
+}
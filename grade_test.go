@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mjwong/sudoku2/format"
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+func TestRate(t *testing.T) {
+	tests := []struct {
+		prof       Profile
+		er, ep, ed float64
+		sum        float64
+		band       string
+	}{
+		{Profile{Steps: []int{3, 3}, Solved: true}, 1.5, 1.5, 1.5, 3.0, "Easy"},
+		{Profile{Steps: []int{3, 1, 3}, Solved: true}, 2.3, 1.5, 1.5, 5.3, "Medium"},
+		{Profile{Steps: []int{5, 20, 1}, Solved: true}, 3.2, 3.2, 3.0, 8.5, "Expert"},
+		{Profile{Steps: []int{5}, Solved: false}, 9.0, 9.0, 3.0, 12.0, "Diabolical"},
+	}
+
+	for _, tc := range tests {
+		r := rate(tc.prof)
+		if r.ER != tc.er || r.EP != tc.ep || r.ED != tc.ed || r.Max != tc.er || r.Band != tc.band {
+			t.Fatalf("%v: expected ER %.1f/EP %.1f/ED %.1f %s but got %v.\n", tc.prof.Steps, tc.er, tc.ep, tc.ed, tc.band, r)
+		}
+		if r.Sum < tc.sum-0.01 || r.Sum > tc.sum+0.01 {
+			t.Fatalf("%v: expected sum %.1f but got %.1f.\n", tc.prof.Steps, tc.sum, r.Sum)
+		}
+	}
+}
+
+// the puzzle files are tagged with their current rating
+func TestRatingTags(t *testing.T) {
+	s := newSolver()
+
+	for _, fname := range []string{"difficult1.txt", "difficult3.txt", "difficult5.txt", "expert2.txt", "expert3.txt", "testXW1.txt"} {
+		b, err := os.ReadFile(fname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(string(b), "\n")
+
+		want := "# Rating: " + rate(s.solveLogic(lines[0])).String()
+		if len(lines) < 2 || lines[1] != want {
+			t.Fatalf("%s: expected %q but got %q.\n", fname, want, lines[1])
+		}
+	}
+}
+
+// Tagging rates every puzzle of a file and leaves the other lines alone
+func TestTagRatings(t *testing.T) {
+	s := newSolver()
+	dir := t.TempDir()
+
+	p1 := "...15....91..764..5.6.4.3........69.6..5.4..7.71........7.3.9.6..386..15....95..." // difficult1.txt
+	p2 := ".4...8..37..4.38...3..16..49.4.6.38..6..3.49..23.4...64..12..3.31268..45...3.4.1." // testXW1.txt
+	ratings := []Rating{rate(s.solveLogic(p1)), rate(s.solveLogic(p2))}
+
+	fname := filepath.Join(dir, "collection.txt")
+	content := "# my collection\n" + p1 + "\n# Rating: old\n\n" + strings.ReplaceAll(p2, ".", "0") + "  # second\n"
+	if err := os.WriteFile(fname, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	puzzles, err := readPuzzles(fname, "")
+	if err != nil || checkLineFile(fname) != nil {
+		t.Fatal(err)
+	}
+	if err := tagRatings(fname, puzzles, ratings); err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := os.ReadFile(fname)
+	want := "# my collection\n" + p1 + "\n# Rating: " + ratings[0].String() + "\n\n" + strings.ReplaceAll(p2, ".", "0") + "  # second\n# Rating: " + ratings[1].String() + "\n"
+	if string(b) != want {
+		t.Fatalf("Expected\n%s\nbut got\n%s\n", want, b)
+	}
+
+	// grids cannot be tagged
+	grid := filepath.Join(dir, "puzzle.ss")
+	var buf bytes.Buffer
+	if err := format.Write(&buf, "ss", []Intmat{PopulateMat(p1)}); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(grid, buf.Bytes(), 0644)
+	if err := checkLineFile(grid); err == nil || !strings.Contains(err.Error(), "ss") {
+		t.Fatalf("Expected an error for a .ss file but got %v.\n", err)
+	}
+}
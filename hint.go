@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mjwong/sudoku2/hint"
+	. "github.com/mjwong/sudoku2/lib"
+	. "github.com/mjwong/sudoku2/linkedlist"
+)
+
+// Hints run the rules of the solver
+func init() {
+	hint.SetFinder(nextStep)
+}
+
+// Find the next step of the simplest technique for the grid m. The candidates are the
+// pencil marks if given, otherwise those left by the digits placed. Runs on a solver of
+// its own. Returns false if the rules find no step.
+func nextStep(m Intmat, marks *Pmat) (Event, bool) {
+	restore := quiet()
+	defer restore()
+
+	for _, t := range techniques {
+		s := newSolver()
+		s.setCandidates(m, marks)
+		s.explaining = true
+		t.fn(s)
+		if list := s.explained(); len(list) > 0 {
+			return list[0], true
+		}
+	}
+	return Event{}, false
+}
+
+// Set up the solver for the grid m with copies of the candidates. An empty cell without
+// marks takes the candidates left by the digits placed.
+func (s *solver) setCandidates(m Intmat, marks *Pmat) {
+	s.mat = m
+	s.emptyCnt = CountEmpty(m)
+	_, s.mat2 = GetPossibleMat(m)
+
+	if marks != nil {
+		for i := 0; i < N; i++ {
+			for j := 0; j < N; j++ {
+				if m[i][j] == 0 && len(marks[i][j]) > 0 {
+					s.mat2[i][j] = append([]int(nil), marks[i][j]...)
+				}
+			}
+		}
+	}
+	s.emptyL = ListFromPossibleMat(s.mat, s.mat2)
+	s.clearHistory()
+}
+
+// Parse pencil marks: one field per cell in row order, separated by white space. A field
+// lists the candidates of the cell, e.g. 137. A filled cell, or one without marks, is 0
+// or a dot.
+func parsePencilMarks(s string) (Pmat, error) {
+	var marks Pmat
+
+	fields := strings.Fields(s)
+	if len(fields) != N*N {
+		return marks, fmt.Errorf("expected %d cells of pencil marks but got %d", N*N, len(fields))
+	}
+
+	for k, f := range fields {
+		if f == "0" || f == "." {
+			continue
+		}
+		for _, ch := range f {
+			if ch < '1' || ch > '0'+N {
+				return marks, fmt.Errorf("cell r%dc%d: bad candidate %q", k/N+1, k%N+1, ch)
+			}
+			if d := int(ch - '0'); !Contains(marks[k/N][k%N], d) {
+				marks[k/N][k%N] = append(marks[k/N][k%N], d)
+			}
+		}
+	}
+	return marks, nil
+}
+
+// Read the pencil marks file
+func readPencilMarks(fname string) (Pmat, error) {
+	b, err := os.ReadFile(fname)
+	if err != nil {
+		return Pmat{}, err
+	}
+	return parsePencilMarks(string(b))
+}
+
+// Give a hint: sudoku2 [variant flags] hint [-level 1-4] [-pencil file] [file]
+// Prints the next step of the simplest technique for the puzzle, without solving it.
+// Lower levels only name the technique, the house or the cells.
+func runHint(args []string) {
+	fs := newFlagSet("hint")
+	level := fs.Int("level", hint.Step, "How much to tell: 1 technique, 2 house, 3 cells, 4 the whole step.")
+	marksFile := fs.String("pencil", "", "Pencil marks file, one field of candidates per cell.")
+	fs.Parse(args)
+
+	input, err := readPuzzle(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(ExitInvalid)
+	}
+
+	var marks *Pmat
+	if *marksFile != "" {
+		pm, err := readPencilMarks(*marksFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", *marksFile, err)
+			os.Exit(ExitInvalid)
+		}
+		marks = &pm
+	}
+
+	e, ok := hint.Next(PopulateMat(input), marks)
+	if !ok {
+		fmt.Println("No step found by the rules.")
+		os.Exit(ExitUnsolved)
+	}
+	fmt.Println(hint.Text(e, *level))
+}
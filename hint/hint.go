@@ -0,0 +1,75 @@
+// Package hint gives the next step of the simplest technique for a grid, without
+// solving it, worded at a level of detail: the technique only, the house, the cells or
+// the whole step.
+package hint
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+// Levels of a progressive hint, each telling more than the one before
+const (
+	Technique = 1 + iota // the technique only
+	House                // and the house or digits of the pattern
+	Cells                // and the cells
+	Step                 // the whole step with its placements or eliminations
+)
+
+// Finder finds the next step for the grid m, from the pencil marks if given. Returns
+// false if there is none.
+type Finder func(m Intmat, marks *Pmat) (Event, bool)
+
+var finder Finder
+
+// SetFinder sets how Next finds a step. The solver sets one running its rules, the
+// simplest technique first.
+func SetFinder(f Finder) {
+	finder = f
+}
+
+// Next finds the next step of the simplest technique for the grid m. The candidates are
+// the pencil marks if given, otherwise those left by the digits placed. Returns false if
+// the rules find no step, or no finder is set.
+func Next(m Intmat, marks *Pmat) (Event, bool) {
+	if finder == nil {
+		return Event{}, false
+	}
+	return finder(m, marks)
+}
+
+// Text of the step at the level, e.g. "hidden single", "hidden single in box 2",
+// "hidden single in box 2 at r3c5" and "r3c5=7 (hidden single in box 2)"
+func Text(e Event, level int) string {
+	if level >= Step {
+		return e.String()
+	}
+
+	s := e.Technique
+	if level >= House {
+		if e.House != "" {
+			s += " in " + e.House
+		} else if len(e.Digits) > 0 {
+			var digits []string
+			for _, d := range e.Digits {
+				digits = append(digits, fmt.Sprint(d))
+			}
+			s += " on " + strings.Join(digits, ",")
+		}
+	}
+	if level >= Cells {
+		var cells []string
+		for _, p := range e.Placements {
+			cells = append(cells, CellName(p.Row, p.Col))
+		}
+		if len(cells) == 0 {
+			for _, c := range e.Cells {
+				cells = append(cells, CellName(c.Row, c.Col))
+			}
+		}
+		s += " at " + strings.Join(cells, " ")
+	}
+	return s
+}
@@ -0,0 +1,48 @@
+package hint
+
+import (
+	"testing"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+func TestNext(t *testing.T) {
+	defer SetFinder(finder)
+
+	SetFinder(nil)
+	if _, ok := Next(Intmat{}, nil); ok {
+		t.Fatal("Expected no step without a finder.")
+	}
+
+	step := Event{Technique: TechNakedSingle, Placements: []Placement{{Row: 2, Col: 8, Dig: 1}}}
+	SetFinder(func(m Intmat, marks *Pmat) (Event, bool) { return step, marks == nil })
+	if e, ok := Next(Intmat{}, nil); !ok || e.String() != "r3c9=1 (naked single)" {
+		t.Fatalf("Expected r3c9=1 (naked single) but got %v.\n", e)
+	}
+	if _, ok := Next(Intmat{}, &Pmat{}); ok {
+		t.Fatal("Expected the marks to be passed to the finder.")
+	}
+}
+
+func TestText(t *testing.T) {
+	e := Event{Technique: TechNakedPair, Digits: []int{3, 7}, House: RowName(1),
+		Cells: []Coord{{Row: 1, Col: 0}, {Row: 1, Col: 4}},
+		Elims: []Elim{{Row: 1, Col: 2, Dig: 3}}}
+	expected := []string{
+		"naked pair",
+		"naked pair in row 2",
+		"naked pair in row 2 at r2c1 r2c5",
+		"naked pair 3,7 in row 2 r2c1 r2c5 => r2c3<>3",
+	}
+
+	for k, exp := range expected {
+		if s := Text(e, k+1); s != exp {
+			t.Fatalf("Level %d: expected %q but got %q.\n", k+1, exp, s)
+		}
+	}
+
+	x := Event{Technique: TechXWing, Digits: []int{4}}
+	if s := Text(x, House); s != "x-wing on 4" {
+		t.Fatalf("Expected x-wing on 4 but got %q.\n", s)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mjwong/sudoku2/hint"
+	. "github.com/mjwong/sudoku2/lib"
+	. "github.com/mjwong/sudoku2/linkedlist"
+)
+
+func TestNextStep(t *testing.T) {
+	s := newSolver()
+
+	input, err := readPuzzle("difficult1.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := PopulateMat(input)
+	sol, _ := SolveUnique(m)
+
+	// the state of the solver is left alone
+	s.PrepPmat(input)
+	before, elems := s.mat, s.emptyL.CountElem()
+
+	e, ok := nextStep(m, nil)
+	if !ok {
+		t.Fatal("Expected a step.")
+	}
+	if s := e.String(); s != "r3c9=1 (hidden single in box 3)" {
+		t.Fatalf("Expected r3c9=1 (hidden single in box 3) but got %s.\n", s)
+	}
+	if s.mat != before || s.emptyL.CountElem() != elems || s.explaining {
+		t.Fatal("Expected the state of the solver to be unchanged.")
+	}
+
+	// with the solution as the pencil marks, the first 1 in row order is a hidden single
+	var marks Pmat
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			if m[i][j] == 0 {
+				marks[i][j] = []int{sol[i][j]}
+			}
+		}
+	}
+	e, ok = nextStep(m, &marks)
+	if !ok || len(e.Placements) != 1 {
+		t.Fatalf("Expected a placement but got %v.\n", e)
+	}
+	p := e.Placements[0]
+	for k := 0; k < N*N; k++ {
+		if m[k/N][k%N] == 0 && sol[k/N][k%N] == 1 {
+			if p.Row != k/N || p.Col != k%N || p.Dig != 1 {
+				t.Fatalf("Expected %s=1 but got %v.\n", CellName(k/N, k%N), e)
+			}
+			break
+		}
+	}
+	if len(marks[p.Row][p.Col]) != 1 {
+		t.Fatal("Expected the pencil marks to be unchanged.")
+	}
+
+	// hints run the rules
+	if h, ok := hint.Next(m, &marks); !ok || !reflect.DeepEqual(h, e) {
+		t.Fatalf("Expected hint.Next to give %v but got %v.\n", e, h)
+	}
+
+	// a full grid has no step
+	if _, ok := nextStep(sol, nil); ok {
+		t.Fatal("Expected no step for a full grid.")
+	}
+}
+
+func TestParsePencilMarks(t *testing.T) {
+	fields := make([]string, N*N)
+	for k := range fields {
+		fields[k] = "."
+	}
+	fields[0], fields[10] = "137", "0"
+
+	marks, err := parsePencilMarks(strings.Join(fields, " "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IntArrayEquals(marks[0][0], []int{1, 3, 7}) || marks[1][1] != nil {
+		t.Fatalf("Expected [1 3 7] and [] but got %v and %v.\n", marks[0][0], marks[1][1])
+	}
+
+	fields[5] = "1a"
+	if _, err := parsePencilMarks(strings.Join(fields, " ")); err == nil || !strings.Contains(err.Error(), "r1c6") {
+		t.Fatalf("Expected an error at r1c6 but got %v.\n", err)
+	}
+	if _, err := parsePencilMarks("123"); err == nil {
+		t.Fatal("Expected an error for too few cells.")
+	}
+}
+
+// The X-wing drawn by the render tests is the step the rules take
+func TestNextXWing(t *testing.T) {
+	input, err := readPuzzle("testXW1.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := PopulateMat(input)
+	_, pm := GetPossibleMat(m)
+
+	pair, ok := nextStep(m, &pm)
+	if !ok || pair.Technique != TechNakedPair {
+		t.Fatalf("Expected a naked pair but got %v.\n", pair)
+	}
+	ApplyElims(&pm, pair.Elims)
+
+	e, ok := nextStep(m, &pm)
+	if s := e.String(); !ok || s != "x-wing 2 r1r3 c1c4 => r1c7<>2, r1c8<>2, r3c7<>2, r3c8<>2" {
+		t.Fatalf("Expected the X-wing on 2 in rows 1 and 3 but got %s.\n", s)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"sort"
+
+	. "github.com/mjwong/sudoku2/lib"
+	. "github.com/mjwong/sudoku2/linkedlist"
+)
+
+// Operation of the rules on mat, mat2 and emptyL
+type operation struct {
+	place         bool // place the digit, otherwise erase the candidate
+	row, col, dig int
+	vals          []int // candidates of the cell before the digit was placed
+}
+
+// Record an operation of the rules. A new operation cannot be redone after an undo.
+func (s *solver) record(o operation) {
+	s.history = append(s.history, o)
+	s.undone = nil
+}
+
+// Forget the history, e.g. for a new puzzle
+func (s *solver) clearHistory() {
+	s.history, s.undone = nil, nil
+}
+
+// Position in the history to roll back to, e.g. before trying a digit
+func (s *solver) snapshot() int {
+	return len(s.history)
+}
+
+// Undo the operations done since the snapshot
+func (s *solver) rollback(snap int) {
+	for len(s.history) > snap {
+		s.undo()
+	}
+}
+
+// Undo the latest operation. Returns false if there is none.
+func (s *solver) undo() bool {
+	if len(s.history) == 0 {
+		return false
+	}
+	o := s.history[len(s.history)-1]
+	s.history = s.history[:len(s.history)-1]
+
+	if o.place {
+		s.mat[o.row][o.col] = 0
+		s.mat2[o.row][o.col] = append([]int(nil), o.vals...)
+		s.emptyL.InsNode(&Cell{Row: o.row, Col: o.col, Vals: append([]int(nil), o.vals...)})
+		s.emptyCnt++
+	} else {
+		s.mat2[o.row][o.col] = insertSorted(s.mat2[o.row][o.col], o.dig)
+		node := s.emptyL.GetNodeFoRCell(o.row, o.col)
+		node.Vals = insertSorted(node.Vals, o.dig)
+	}
+
+	s.undone = append(s.undone, o)
+	return true
+}
+
+// Redo the latest operation undone. Returns false if there is none.
+func (s *solver) redo() bool {
+	if len(s.undone) == 0 {
+		return false
+	}
+	o := s.undone[len(s.undone)-1]
+	s.undone = s.undone[:len(s.undone)-1]
+
+	saved := s.undone
+	if o.place {
+		s.placeDigit(s.emptyL.GetNodeFoRCell(o.row, o.col), o.dig)
+	} else {
+		s.eraseCandidate(o.row, o.col, o.dig)
+	}
+	s.undone = saved
+	return true
+}
+
+// Copy of the sorted candidates with the digit added
+func insertSorted(vals []int, dig int) []int {
+	list := append([]int(nil), vals...)
+	if Contains(list, dig) {
+		return list
+	}
+	list = append(list, dig)
+	sort.Ints(list)
+	return list
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/mjwong/sudoku2/linkedlist"
+)
+
+// Candidates of the empty list in its order
+func listString(l *LinkedList) string {
+	s := ""
+	for currN := l.Head; currN != nil; currN = currN.Next {
+		s += fmt.Sprintf("[%d,%d]%v", currN.Row, currN.Col, currN.Vals)
+	}
+	return s
+}
+
+func TestUndoRedo(t *testing.T) {
+	s := newSolver()
+
+	input, err := readPuzzle("difficult3.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.PrepPmat(input)
+	if s.undo() || s.redo() {
+		t.Fatal("Expected nothing to undo or redo.")
+	}
+
+	restore := quiet()
+	mat0, pm0, list0, cnt0 := s.mat, fmt.Sprint(s.mat2), listString(s.emptyL), s.emptyCnt
+	snap := s.snapshot()
+	s.rule3()
+	s.rule1()
+	s.rule5()
+	mat1, pm1, list1, cnt1 := s.mat, fmt.Sprint(s.mat2), listString(s.emptyL), s.emptyCnt
+	restore()
+
+	ops := len(s.history) - snap
+	if ops == 0 || mat1 == mat0 {
+		t.Fatal("Expected the rules to place digits.")
+	}
+
+	s.rollback(snap)
+	if s.mat != mat0 || fmt.Sprint(s.mat2) != pm0 || listString(s.emptyL) != list0 || s.emptyCnt != cnt0 {
+		t.Fatal("Expected the state before the rules after the rollback.")
+	}
+	if len(s.undone) != ops {
+		t.Fatalf("Expected %d operations to redo but got %d.\n", ops, len(s.undone))
+	}
+
+	for s.redo() {
+	}
+	if s.mat != mat1 || fmt.Sprint(s.mat2) != pm1 || listString(s.emptyL) != list1 || s.emptyCnt != cnt1 {
+		t.Fatal("Expected the state after the rules after redoing.")
+	}
+	if len(s.history) != snap+ops {
+		t.Fatalf("Expected %d operations but got %d.\n", snap+ops, len(s.history))
+	}
+
+	// a new operation cannot be redone after an undo
+	s.undo()
+	node := s.emptyL.Head
+	s.eraseCandidate(node.Row, node.Col, node.Vals[0])
+	if s.redo() {
+		t.Fatal("Expected nothing to redo after a new operation.")
+	}
+}
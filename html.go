@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"strings"
+
+	. "github.com/mjwong/sudoku2/lib"
+	. "github.com/mjwong/sudoku2/linkedlist"
+	"github.com/mjwong/sudoku2/render"
+)
+
+// Step of a walkthrough with the grid and the candidates before it
+type walkStep struct {
+	Event Event
+	Grid  Intmat
+	Cands Pmat
+}
+
+// Walk through the logical solve of the puzzle one step at a time, the simplest
+// technique first. Returns the steps, the grid and candidates at the end, and whether
+// the rules solved the puzzle.
+func walkSolve(m Intmat) ([]walkStep, Intmat, Pmat, bool) {
+	var steps []walkStep
+	_, marks := GetPossibleMat(m)
+
+	for CountEmpty(m) > 0 {
+		e, ok := nextStep(m, &marks)
+		if !ok {
+			break
+		}
+		steps = append(steps, walkStep{Event: e, Grid: m, Cands: copyPmat(marks)})
+
+		for _, p := range e.Placements {
+			m[p.Row][p.Col] = p.Dig
+			marks[p.Row][p.Col] = nil
+		}
+		for _, el := range e.Elims {
+			marks[el.Row][el.Col] = EraseFromSlice(append([]int(nil), marks[el.Row][el.Col]...), el.Dig)
+		}
+		// the candidates left by the digits placed
+		_, left := GetPossibleMat(m)
+		for i := 0; i < N; i++ {
+			for j := 0; j < N; j++ {
+				var list []int
+				for _, d := range marks[i][j] {
+					if Contains(left[i][j], d) {
+						list = append(list, d)
+					}
+				}
+				marks[i][j] = list
+			}
+		}
+	}
+	return steps, m, marks, CountEmpty(m) == 0
+}
+
+// Deep copy of the candidates, the rules change them in place
+func copyPmat(pm Pmat) Pmat {
+	var out Pmat
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			out[i][j] = append([]int(nil), pm[i][j]...)
+		}
+	}
+	return out
+}
+
+// Row of the summary table of a walkthrough
+type techniqueCount struct {
+	Technique           string
+	Steps, Placed, Elim int
+}
+
+// Counts of the techniques of the steps in the order first used
+func countTechniques(steps []walkStep) []techniqueCount {
+	var list []techniqueCount
+	index := map[string]int{}
+
+	for _, s := range steps {
+		k, ok := index[s.Event.Technique]
+		if !ok {
+			k = len(list)
+			index[s.Event.Technique] = k
+			list = append(list, techniqueCount{Technique: s.Event.Technique})
+		}
+		list[k].Steps++
+		list[k].Placed += len(s.Event.Placements)
+		list[k].Elim += len(s.Event.Elims)
+	}
+	return list
+}
+
+var htmlReport = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 60em; margin: 2em auto; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #999; padding: 0.3em 0.8em; }
+td.n { text-align: right; }
+.step { display: flex; gap: 2em; align-items: center; border-top: 1px solid #ccc; padding: 1em 0; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p><code>{{.Input}}</code></p>
+{{.Start}}
+<h2>Summary</h2>
+<table>
+<tr><th>Technique</th><th>Steps</th><th>Digits placed</th><th>Candidates erased</th></tr>
+{{range .Counts}}<tr><td>{{.Technique}}</td><td class="n">{{.Steps}}</td><td class="n">{{.Placed}}</td><td class="n">{{.Elim}}</td></tr>
+{{end}}</table>
+<p>{{if .Solved}}Solved in {{len .Steps}} steps.{{else}}The rules get stuck after {{len .Steps}} steps with {{.Empty}} cells empty, the rest needs guessing.{{end}}</p>
+<h2>Steps</h2>
+{{range .Steps}}<div class="step">
+{{.Board}}
+<p>{{.Number}}. {{.Text}}</p>
+</div>
+{{end}}<h2>{{if .Solved}}Solution{{else}}Where the rules get stuck{{end}}</h2>
+{{.Final}}
+</body>
+</html>
+`))
+
+// Write the walkthrough of the logical solve of the puzzle as a single HTML file with
+// the boards drawn as inline SVG
+func writeHTMLReport(w io.Writer, title string, m Intmat, cellSize int) error {
+	steps, final, left, solved := walkSolve(m)
+
+	board := func(grid Intmat, opts render.Options) (template.HTML, error) {
+		var b strings.Builder
+		opts.CellSize, opts.Givens = cellSize, &m
+		err := render.SVG(&b, grid, opts)
+		return template.HTML(b.String()), err
+	}
+
+	type htmlStep struct {
+		Number int
+		Text   string
+		Board  template.HTML
+	}
+	doc := struct {
+		Title, Input string
+		Start, Final template.HTML
+		Counts       []techniqueCount
+		Steps        []htmlStep
+		Solved       bool
+		Empty        int
+	}{Title: title, Input: MatToString(m), Counts: countTechniques(steps), Solved: solved, Empty: CountEmpty(final)}
+
+	var err error
+	if doc.Start, err = board(m, render.Options{}); err != nil {
+		return err
+	}
+	for k := range steps {
+		s := &steps[k]
+		svg, err := board(s.Grid, render.Options{Cands: &s.Cands, Step: &s.Event})
+		if err != nil {
+			return err
+		}
+		doc.Steps = append(doc.Steps, htmlStep{Number: k + 1, Text: s.Event.String(), Board: svg})
+	}
+	opts := render.Options{}
+	if !solved {
+		opts.Cands = &left
+	}
+	if doc.Final, err = board(final, opts); err != nil {
+		return err
+	}
+
+	return htmlReport.Execute(w, doc)
+}
+
+// Write an HTML walkthrough: sudoku2 [variant flags] html [-o file.html] [-title t] [-size 40] [file]
+// Walks through the logical solve of the puzzle with a board for each step. Writes to
+// stdout without -o.
+func runHTML(args []string) {
+	fs := newFlagSet("html")
+	out := fs.String("o", "", "Output file, stdout if not set.")
+	title := fs.String("title", "Solve path", "Title of the page.")
+	size := fs.Int("size", 40, "Pixels per cell of the boards.")
+	fs.Parse(args)
+
+	input, err := readPuzzle(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(ExitInvalid)
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		if w, err = os.Create(*out); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(ExitUnsolved)
+		}
+		defer w.Close()
+	}
+	if err := writeHTMLReport(w, *title, PopulateMat(input), *size); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(ExitUnsolved)
+	}
+}
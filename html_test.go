@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+func TestWalkSolve(t *testing.T) {
+	input, err := readPuzzle("testXW1.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := PopulateMat(input)
+
+	steps, final, _, solved := walkSolve(m)
+	sol, _ := SolveUnique(m)
+	if !solved || final != sol {
+		t.Fatalf("Expected the solution but got %s.\n", MatToString(final))
+	}
+
+	// each step starts from the grid the step before left
+	for k := 1; k < len(steps); k++ {
+		prev, cur := steps[k-1], steps[k]
+		if CountEmpty(cur.Grid) != CountEmpty(prev.Grid)-len(prev.Event.Placements) {
+			t.Fatalf("Step %d: expected %d empty cells but got %d.\n", k+1,
+				CountEmpty(prev.Grid)-len(prev.Event.Placements), CountEmpty(cur.Grid))
+		}
+	}
+
+	counts := map[string]int{}
+	for _, c := range countTechniques(steps) {
+		counts[c.Technique] = c.Steps
+	}
+	if counts[TechXWing] != 1 || counts[TechNakedPair] != 1 {
+		t.Fatalf("Expected an X-wing and a naked pair but got %v.\n", counts)
+	}
+}
+
+func TestHTMLReport(t *testing.T) {
+	input, err := readPuzzle("difficult1.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := writeHTMLReport(&b, "Solve <1>", PopulateMat(input), 30); err != nil {
+		t.Fatal(err)
+	}
+	doc := b.String()
+
+	steps, _, _, _ := walkSolve(PopulateMat(input))
+	if !strings.Contains(doc, "<title>Solve &lt;1&gt;</title>") {
+		t.Fatal("Expected the escaped title.")
+	}
+	if !strings.Contains(doc, fmt.Sprintf("Solved in %d steps.", len(steps))) {
+		t.Fatalf("Expected %d steps.\n", len(steps))
+	}
+	// a board for the start, each step and the solution
+	if n := strings.Count(doc, "<svg "); n != len(steps)+2 {
+		t.Fatalf("Expected %d boards but got %d.\n", len(steps)+2, n)
+	}
+	if !strings.Contains(doc, steps[0].Event.String()) {
+		t.Fatalf("Expected the explanation %q.\n", steps[0].Event.String())
+	}
+}
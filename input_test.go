@@ -0,0 +1,75 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+func TestParseMat(t *testing.T) {
+	input := "...15....91..764..5.6.4.3........69.6..5.4..7.71........7.3.9.6..386..15....95..."
+	expected := PopulateMat(input)
+
+	for _, s := range []string{
+		input,
+		strings.ReplaceAll(input, ".", "0"),
+		strings.ReplaceAll(input, ".", "_"),
+		strings.ReplaceAll(input, ".", "*"),
+		" " + input[:40] + "\n\t" + input[40:] + " ",
+	} {
+		m, err := ParseMat(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m != expected {
+			t.Fatalf("Expected %s but got %s.\n", input, MatToString(m))
+		}
+	}
+
+	tests := []struct {
+		s, err string
+	}{
+		{input[:80], "expected 81 cells but got 80"},
+		{input + "1", "expected 81 cells but got 82"},
+		{"x" + input[1:], `bad character 'x' in cell 1`},
+	}
+	for _, tc := range tests {
+		if _, err := ParseMat(tc.s); err == nil || err.Error() != tc.err {
+			t.Fatalf("Expected %q but got %v.\n", tc.err, err)
+		}
+	}
+}
+
+func TestReadPuzzles(t *testing.T) {
+	list, err := ReadPuzzleFile("puzzles1.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		line  int
+		fname string
+	}{
+		{2, "difficult1.txt"},
+		{5, "difficult3.txt"},
+		{6, "expert1.txt"},
+	}
+	if len(list) != len(expected) {
+		t.Fatalf("Expected %d puzzles but got %d.\n", len(expected), len(list))
+	}
+	for k, exp := range expected {
+		input, err := readPuzzle(exp.fname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if list[k].Line != exp.line || MatToString(list[k].Mat) != input {
+			t.Fatalf("Expected %s on line %d but got %s on line %d.\n", exp.fname, exp.line, MatToString(list[k].Mat), list[k].Line)
+		}
+	}
+
+	_, err = ReadPuzzles(strings.NewReader("# comment\n\n" + strings.Repeat(".", 80) + "\n"))
+	if err == nil || err.Error() != "line 3: expected 81 cells but got 80" {
+		t.Fatalf("Expected an error on line 3 but got %v.\n", err)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+var (
+	jigsaw1        = "..35.2..8..4...23...87...9.........44......2....1......7.65.........1.5.6.1......" // jigsaw1.txt
+	jigsaw1Sol     = "963542178784916235128735496596827314415369827237184569372658941849271653651493782"
+	jigsaw1Regions = "000111222 000111222 003114222 033144555 333444555 333444558 666777588 666777888 666777888"
+)
+
+func TestParseRegions(t *testing.T) {
+	r, err := ParseRegions(jigsaw1Regions)
+	if err != nil {
+		t.Fatalf("Expected valid region map but got %v.\n", err)
+	}
+
+	if r[2][2] != 3 || r[3][0] != 0 || r[6][6] != 5 {
+		t.Fatalf("Wrong regions for cells [2,2], [3,0] and [6,6]: %d %d %d.\n", r[2][2], r[3][0], r[6][6])
+	}
+
+	if _, err := ParseRegions("0001112223"); err == nil {
+		t.Fatal("Expected error for short region map.")
+	}
+
+	bad := "000011222 000111222 003114222 033144555 333444555 333444558 666777588 666777888 666777888"
+	r, _ = ParseRegions(bad)
+	if err := SetRegions(r); err == nil {
+		t.Fatal("Expected error for region with wrong no. of cells.")
+	}
+}
+
+func TestJigsaw(t *testing.T) {
+	s := newSolver()
+
+	r, _ := ParseRegions(jigsaw1Regions)
+	if err := SetRegions(r); err != nil {
+		t.Fatal(err)
+	}
+	defer SetStdRegions()
+
+	if IsStdRegions() {
+		t.Fatal("Expected jigsaw regions.")
+	}
+
+	s.PrepPmat(jigsaw1)
+
+	// [3,0] belongs to the top left region, which has 3 given at [0,2]
+	if Contains(s.mat2[3][0], 3) {
+		t.Fatalf("Cell [3,0] should not contain 3 but got %v.\n", s.mat2[3][0])
+	}
+
+	s.RuleLoop(s.rule3, RuleTable[3], Zero)
+	s.RuleLoop(s.rule1, RuleTable[1], Zero)
+
+	s.mat3 = s.mat
+	s.iterMat(s.emptyL.Head)
+
+	if MatToString(s.mat3) != jigsaw1Sol {
+		t.Fatalf("Expected %s but got %s.\n", jigsaw1Sol, MatToString(s.mat3))
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+var killer1Sol = "146572389529683741837194526612345978794268153385917462273851694468739215951426837"
+
+func TestParseCages(t *testing.T) {
+	cages, err := ParseCages("# comment\n\n3 r1c1 r1c2\n17 R2C1 r2c2\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cages) != 2 || cages[1].Sum != 17 || cages[1].Cells[1] != (Coord{Row: 1, Col: 1}) {
+		t.Fatalf("Expected 2 cages but got %v.\n", cages)
+	}
+
+	_, err = ParseCages("3 r1c1 r1c2\n5 r1c3 r0c4\n")
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("Expected error at line 2 but got %v.\n", err)
+	}
+
+	defer ClearConstraints()
+	cages, _ = ParseCages("3 r1c1 r1c2\n4 r1c2 r1c3\n")
+	if err := SetCages(cages); err == nil {
+		t.Fatal("Expected error for cell in 2 cages.")
+	}
+}
+
+func TestCagePrune(t *testing.T) {
+	s := newSolver()
+
+	defer ClearConstraints()
+
+	// 3 in 2 cells can only be 1+2. 24 in 3 cells can only be 7+8+9.
+	cages, _ := ParseCages("3 r1c1 r1c2\n24 r2c1 r2c2 r2c3\n")
+	if err := SetCages(cages); err != nil {
+		t.Fatal(err)
+	}
+
+	s.PrepPmat(strings.Repeat(".", 81))
+
+	if !IntArrayEquals(s.mat2[0][0], []int{1, 2}) {
+		t.Fatalf("Expected [1 2] but got %v.\n", s.mat2[0][0])
+	}
+	if !IntArrayEquals(s.mat2[1][2], []int{7, 8, 9}) {
+		t.Fatalf("Expected [7 8 9] but got %v.\n", s.mat2[1][2])
+	}
+}
+
+// 45 rule: the cages in row 0 add up to 36 so the innie [0,8] must be 9
+func TestCageInnies(t *testing.T) {
+	s := newSolver()
+
+	defer ClearConstraints()
+
+	cages, _ := ParseCages("10 r1c1 r1c2 r1c3 r1c4\n26 r1c5 r1c6 r1c7 r1c8\n")
+	if err := SetCages(cages); err != nil {
+		t.Fatal(err)
+	}
+
+	s.PrepPmat(strings.Repeat(".", 81))
+
+	if !IntArrayEquals(s.mat2[0][8], []int{9}) {
+		t.Fatalf("Expected [9] but got %v.\n", s.mat2[0][8])
+	}
+}
+
+func TestKiller(t *testing.T) {
+	s := newSolver()
+
+	defer ClearConstraints()
+
+	if err := ReadCages("killer1_cages.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile("killer1.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.PrepPmat(strings.TrimSpace(string(b)))
+
+	s.RuleLoop(s.rule3, RuleTable[3], Zero)
+	s.RuleLoop(s.rule1, RuleTable[1], Zero)
+
+	s.mat3 = s.mat
+	s.iterMat(s.emptyL.Head)
+
+	if MatToString(s.mat3) != killer1Sol {
+		t.Fatalf("Expected %s but got %s.\n", killer1Sol, MatToString(s.mat3))
+	}
+
+	if !ConstraintsHold(s.mat3) {
+		t.Fatal("Expected cage sums to hold.")
+	}
+}
@@ -0,0 +1,93 @@
+package lib
+
+// Chess variants: equal digits may not be a knight's move (anti-knight) or a king's
+// move (anti-king) apart, and orthogonally adjacent cells may not hold consecutive
+// digits (non-consecutive).
+
+var (
+	knightMoves = []Coord{{-2, -1}, {-2, 1}, {-1, -2}, {-1, 2}, {1, -2}, {1, 2}, {2, -1}, {2, 1}}
+	kingMoves   = []Coord{{-1, -1}, {-1, 0}, {-1, 1}, {0, -1}, {0, 1}, {1, -1}, {1, 0}, {1, 1}}
+	orthoMoves  = []Coord{{-1, 0}, {0, -1}, {0, 1}, {1, 0}}
+)
+
+// Equal digits may not be one of the moves apart
+type moveConstraint struct {
+	moves []Coord
+}
+
+// Orthogonally adjacent cells may not hold consecutive digits
+type nonConsecutive struct{}
+
+// Cells which are one of the moves away from [row,col]
+func neighbours(row, col int, moves []Coord) []Coord {
+	var list []Coord
+
+	for _, mv := range moves {
+		r, c := row+mv.Row, col+mv.Col
+		if r >= 0 && r < N && c >= 0 && c < N {
+			list = append(list, Coord{Row: r, Col: c})
+		}
+	}
+	return list
+}
+
+func (mc moveConstraint) Valid(m Intmat, row, col, num int) bool {
+	for _, c := range neighbours(row, col, mc.moves) {
+		if m[c.Row][c.Col] == num {
+			return false
+		}
+	}
+	return true
+}
+
+// Prune erases the digit of every filled cell from the empty cells a move away
+func (mc moveConstraint) Prune(m Intmat, m2 Pmat) []Elim {
+	var elims []Elim
+
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			if m[i][j] == 0 {
+				continue
+			}
+			for _, c := range neighbours(i, j, mc.moves) {
+				if m[c.Row][c.Col] == 0 && Contains(m2[c.Row][c.Col], m[i][j]) {
+					elims = append(elims, Elim{Row: c.Row, Col: c.Col, Dig: m[i][j]})
+				}
+			}
+		}
+	}
+	return elims
+}
+
+func (nc nonConsecutive) Valid(m Intmat, row, col, num int) bool {
+	for _, c := range neighbours(row, col, orthoMoves) {
+		if v := m[c.Row][c.Col]; v != 0 && (v == num-1 || v == num+1) {
+			return false
+		}
+	}
+	return true
+}
+
+// Prune erases the digits either side of every filled cell from its empty orthogonal neighbours
+func (nc nonConsecutive) Prune(m Intmat, m2 Pmat) []Elim {
+	var elims []Elim
+
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			if m[i][j] == 0 {
+				continue
+			}
+			for _, c := range neighbours(i, j, orthoMoves) {
+				if m[c.Row][c.Col] != 0 {
+					continue
+				}
+				for _, d := range []int{m[i][j] - 1, m[i][j] + 1} {
+					if Contains(m2[c.Row][c.Col], d) {
+						elims = append(elims, Elim{Row: c.Row, Col: c.Col, Dig: d})
+					}
+				}
+			}
+		}
+	}
+	return elims
+}
@@ -0,0 +1,80 @@
+package lib
+
+// Elim is a candidate digit to be erased from a cell of the possibility matrix
+type Elim struct {
+	Row, Col, Dig int
+}
+
+// Constraint is an extra rule of a sudoku variant, e.g. a killer cage.
+type Constraint interface {
+	// Valid returns true if num may be placed at [row,col] of m without breaking the constraint
+	Valid(m Intmat, row, col, num int) bool
+	// Prune returns the candidates of m2 which can no longer satisfy the constraint
+	Prune(m Intmat, m2 Pmat) []Elim
+}
+
+// Constraints of the current variant. Empty for classic sudoku.
+var Constraints []Constraint
+
+func AddConstraint(c Constraint) {
+	Constraints = append(Constraints, c)
+}
+
+// Remove all constraints (and the cages and marks that created them)
+func ClearConstraints() {
+	Constraints = nil
+	Cages = nil
+	Relations = nil
+	Parities = nil
+}
+
+// Returns true if num may be placed at [row,col] of m under every constraint
+func ConstraintsValid(m Intmat, row, col, num int) bool {
+	for _, c := range Constraints {
+		if !c.Valid(m, row, col, num) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns true if every filled cell of m satisfies all the constraints
+func ConstraintsHold(m Intmat) bool {
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			if m[i][j] != 0 {
+				num := m[i][j]
+				m[i][j] = 0
+				ok := ConstraintsValid(m, i, j, num)
+				m[i][j] = num
+				if !ok {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// Collect the eliminations of all constraints. Duplicates are removed.
+func PruneConstraints(m Intmat, m2 Pmat) []Elim {
+	var list []Elim
+	seen := map[Elim]bool{}
+
+	for _, c := range Constraints {
+		for _, e := range c.Prune(m, m2) {
+			if !seen[e] {
+				seen[e] = true
+				list = append(list, e)
+			}
+		}
+	}
+	return list
+}
+
+// Erase the eliminations from the possibility matrix
+func ApplyElims(m2 *Pmat, elims []Elim) {
+	for _, e := range elims {
+		m2[e.Row][e.Col] = EraseFromSlice(m2[e.Row][e.Col], e.Dig)
+	}
+}
@@ -0,0 +1,111 @@
+package lib
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Techniques named in the explanation of a solve
+const (
+	TechNakedSingle  = "naked single"
+	TechHiddenSingle = "hidden single"
+	TechNakedPair    = "naked pair"
+	TechXWing        = "x-wing"
+)
+
+// Placement of a digit in a cell
+type Placement struct {
+	Row, Col, Dig int
+}
+
+// Event is one step of a solve: the technique with the cells and house of its pattern,
+// the digits it placed and the candidates it erased.
+type Event struct {
+	Technique  string
+	Digits     []int   // digits of the pattern, e.g. the pair of a naked pair
+	Cells      []Coord // cells of the pattern
+	House      string  // house of the pattern, e.g. "row 3" or "box 2"
+	Placements []Placement
+	Elims      []Elim
+}
+
+// Name of a cell in the standard notation, e.g. r3c5. Rows and cols count from 1.
+func CellName(row, col int) string {
+	return fmt.Sprintf("r%dc%d", row+1, col+1)
+}
+
+// Names of houses counting from 1
+func RowName(row int) string { return fmt.Sprintf("row %d", row+1) }
+func ColName(col int) string { return fmt.Sprintf("col %d", col+1) }
+func BoxName(blk int) string { return fmt.Sprintf("box %d", blk+1) }
+func HouseName(h int) string { return fmt.Sprintf("house %d", h+1) }
+
+// Render the event in the standard notation, e.g.
+//
+//	r3c5=7 (hidden single in box 2)
+//	naked pair 3,7 in row 2 r2c1 r2c5 => r2c3<>3, r2c7<>7
+//	x-wing 4 r2r7 c3c8 => r5c3<>4
+//
+// Singles show their placements, other techniques their eliminations.
+func (e Event) String() string {
+	if len(e.Placements) > 0 {
+		var list []string
+		for _, p := range e.Placements {
+			list = append(list, fmt.Sprintf("%s=%d", CellName(p.Row, p.Col), p.Dig))
+		}
+		if e.House == "" {
+			return fmt.Sprintf("%s (%s)", strings.Join(list, ", "), e.Technique)
+		}
+		return fmt.Sprintf("%s (%s in %s)", strings.Join(list, ", "), e.Technique, e.House)
+	}
+
+	parts := []string{e.Technique}
+	if len(e.Digits) > 0 {
+		var digits []string
+		for _, d := range e.Digits {
+			digits = append(digits, fmt.Sprint(d))
+		}
+		parts = append(parts, strings.Join(digits, ","))
+	}
+	if e.House != "" {
+		parts = append(parts, "in "+e.House)
+	}
+	if e.Technique == TechXWing {
+		parts = append(parts, e.lines())
+	} else {
+		for _, c := range e.Cells {
+			parts = append(parts, CellName(c.Row, c.Col))
+		}
+	}
+
+	var elims []string
+	for _, el := range e.Elims {
+		elims = append(elims, fmt.Sprintf("%s<>%d", CellName(el.Row, el.Col), el.Dig))
+	}
+	return strings.Join(parts, " ") + " => " + strings.Join(elims, ", ")
+}
+
+// Rows and cols of the pattern cells, e.g. r2r7 c3c8
+func (e Event) lines() string {
+	rows, cols := map[int]bool{}, map[int]bool{}
+	for _, c := range e.Cells {
+		rows[c.Row] = true
+		cols[c.Col] = true
+	}
+	return lineNames("r", rows) + " " + lineNames("c", cols)
+}
+
+func lineNames(prefix string, set map[int]bool) string {
+	var list []int
+	for k := range set {
+		list = append(list, k)
+	}
+	sort.Ints(list)
+
+	var s string
+	for _, k := range list {
+		s += fmt.Sprintf("%s%d", prefix, k+1)
+	}
+	return s
+}
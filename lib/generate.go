@@ -0,0 +1,331 @@
+package lib
+
+import (
+	"fmt"
+	"math/bits"
+	"math/rand"
+	"strings"
+)
+
+// Symmetries of the clue pattern of a generated puzzle
+const (
+	SymNone       = "none"
+	SymRot180     = "rot180"       // rotational, half turn
+	SymRot90      = "rot90"        // rotational, quarter turn
+	SymDiagonal   = "diagonal"     // mirrored in the main diagonal
+	SymAntiDiag   = "antidiagonal" // mirrored in the anti diagonal
+	SymHorizontal = "horizontal"   // mirrored left to right
+	SymVertical   = "vertical"     // mirrored top to bottom
+)
+
+var symmetries = []string{SymNone, SymRot180, SymRot90, SymDiagonal, SymAntiDiag, SymHorizontal, SymVertical}
+
+// GenOptions of the puzzle generator. A MaxClues of 0 means no maximum.
+type GenOptions struct {
+	Symmetry string
+	MinClues int
+	MaxClues int
+	MaxTries int // full grids to try before giving up, default 20
+	Rand     *rand.Rand
+}
+
+// Returns an error for an unknown symmetry name
+func CheckSymmetry(sym string) error {
+	for _, s := range symmetries {
+		if s == sym {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown symmetry %q, expected one of %s", sym, strings.Join(symmetries, ", "))
+}
+
+// The cells which must be cleared together with [row,col] to keep the symmetry
+func SymmetricCells(sym string, row, col int) []Coord {
+	var list []Coord
+
+	add := func(r, c int) {
+		for _, x := range list {
+			if x.Row == r && x.Col == c {
+				return
+			}
+		}
+		list = append(list, Coord{Row: r, Col: c})
+	}
+
+	add(row, col)
+	switch sym {
+	case SymRot180:
+		add(N-1-row, N-1-col)
+	case SymRot90:
+		add(col, N-1-row)
+		add(N-1-row, N-1-col)
+		add(N-1-col, row)
+	case SymDiagonal:
+		add(col, row)
+	case SymAntiDiag:
+		add(N-1-col, N-1-row)
+	case SymHorizontal:
+		add(row, N-1-col)
+	case SymVertical:
+		add(N-1-row, col)
+	}
+	return list
+}
+
+// Returns true if the clues of m follow the symmetry
+func HasSymmetry(m Intmat, sym string) bool {
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			for _, c := range SymmetricCells(sym, i, j) {
+				if (m[i][j] == 0) != (m[c.Row][c.Col] == 0) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// Count the solutions of m, stopping at limit. Honours the regions, extra houses and
+// constraints of the current variant.
+func CountSolutions(m Intmat, limit int) int {
+	_, cnt := solutions(m, nil, limit)
+	return cnt
+}
+
+// Solve m by backtracking. Returns the first solution and the number of solutions up to 2.
+func SolveUnique(m Intmat) (Intmat, int) {
+	return solutions(m, nil, 2)
+}
+
+// Solve m by backtracking with the digits of each empty cell limited to its candidates
+// in cands, e.g. the pencil marks of a candidate grid. A cell without candidates in
+// cands is not limited. Returns the first solution and the number of solutions up to 2.
+func SolveUniqueCands(m Intmat, cands Pmat) (Intmat, int) {
+	return solutions(m, &cands, 2)
+}
+
+func solutions(m Intmat, cands *Pmat, limit int) (Intmat, int) {
+	var s counter
+
+	s.m = m
+	if cands != nil {
+		for i := 0; i < N; i++ {
+			for j := 0; j < N; j++ {
+				for _, d := range cands[i][j] {
+					s.allowed[i][j] |= 1 << d
+				}
+			}
+		}
+	}
+	s.house = make([]int, len(Houses))
+	if len(Constraints) > 0 && !ConstraintsHold(m) {
+		return m, 0
+	}
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			if d := m[i][j]; d != 0 {
+				if s.used(i, j)&(1<<d) != 0 {
+					return m, 0
+				}
+				s.set(i, j, d)
+			}
+		}
+	}
+	cnt := backtrack(&s, limit, nil, nil)
+	return s.solution, cnt
+}
+
+// Fill an empty grid with random digits, i.e. a random solution of the current variant
+func RandomGrid(r *rand.Rand) (Intmat, bool) {
+	var s counter
+
+	s.house = make([]int, len(Houses))
+	ok := backtrack(&s, 1, r, nil) == 1
+	return s.solution, ok
+}
+
+// Generate a uniquely solvable puzzle and its solution. Clues are removed from a random
+// full grid in random order, keeping the symmetry, as long as the solution stays unique
+// and at least MinClues remain. Full grids are tried until the puzzle has at most MaxClues.
+func Generate(opts GenOptions) (Intmat, Intmat, error) {
+	if opts.Symmetry == "" {
+		opts.Symmetry = SymNone
+	}
+	if err := CheckSymmetry(opts.Symmetry); err != nil {
+		return Intmat{}, Intmat{}, err
+	}
+	if opts.MaxClues > 0 && opts.MaxClues < opts.MinClues {
+		return Intmat{}, Intmat{}, fmt.Errorf("max clues %d is less than min clues %d", opts.MaxClues, opts.MinClues)
+	}
+	if opts.MaxTries <= 0 {
+		opts.MaxTries = 20
+	}
+	if opts.Rand == nil {
+		opts.Rand = rand.New(rand.NewSource(1))
+	}
+
+	best := nsize + 1
+	for try := 0; try < opts.MaxTries; try++ {
+		sol, ok := RandomGrid(opts.Rand)
+		if !ok {
+			return Intmat{}, Intmat{}, fmt.Errorf("no solution for this variant")
+		}
+
+		puzzle := sol
+		clues := nsize
+		for _, p := range opts.Rand.Perm(nsize) {
+			cells := SymmetricCells(opts.Symmetry, p/N, p%N)
+			if puzzle[p/N][p%N] == 0 || clues-len(cells) < opts.MinClues {
+				continue
+			}
+
+			saved := puzzle
+			for _, c := range cells {
+				puzzle[c.Row][c.Col] = 0
+			}
+			if CountSolutions(puzzle, 2) == 1 {
+				clues -= len(cells)
+			} else {
+				puzzle = saved
+			}
+		}
+
+		if opts.MaxClues == 0 || clues <= opts.MaxClues {
+			return puzzle, sol, nil
+		}
+		if clues < best {
+			best = clues
+		}
+	}
+	return Intmat{}, Intmat{}, fmt.Errorf("no puzzle with at most %d clues after %d grids, best was %d", opts.MaxClues, opts.MaxTries, best)
+}
+
+// Grid searched by backtrack: a single grid or a MultiGrid
+type searchGrid interface {
+	// the empty cell with the fewest candidates and its candidates, row -1 if none is empty
+	nextCell() (row, col int, digits []int)
+	set(row, col, d int)
+	unset(row, col, d int)
+	found() // called for each solution
+}
+
+// Count the solutions of g up to limit by backtracking, the cell with the fewest
+// candidates first. Shuffles the candidates if r is set, and counts the digits tried in
+// tries if set. Leaves g as it was.
+func backtrack(g searchGrid, limit int, r *rand.Rand, tries *int) int {
+	row, col, digits := g.nextCell()
+	if row < 0 {
+		g.found()
+		return 1
+	}
+	if r != nil {
+		r.Shuffle(len(digits), func(a, b int) { digits[a], digits[b] = digits[b], digits[a] })
+	}
+
+	cnt := 0
+	for _, d := range digits {
+		if tries != nil {
+			*tries++
+		}
+		g.set(row, col, d)
+		cnt += backtrack(g, limit-cnt, r, tries)
+		g.unset(row, col, d)
+		if cnt >= limit {
+			break
+		}
+	}
+	return cnt
+}
+
+// Solution counter of a single grid using bit masks of the digits in each row, col, block
+// and extra house
+type counter struct {
+	m             Intmat
+	row, col, blk [N]int
+	house         []int     // of each of Houses
+	allowed       [N][N]int // digits an empty cell may take, any if 0
+	solution      Intmat    // first solution found
+	solutions     int
+}
+
+func (s *counter) set(row, col, d int) {
+	bit := 1 << d
+	s.m[row][col] = d
+	s.row[row] |= bit
+	s.col[col] |= bit
+	s.blk[BlkOf(row, col)] |= bit
+	for _, h := range HousesOf(row, col) {
+		s.house[h] |= bit
+	}
+}
+
+func (s *counter) unset(row, col, d int) {
+	bit := 1 << d
+	s.m[row][col] = 0
+	s.row[row] &^= bit
+	s.col[col] &^= bit
+	s.blk[BlkOf(row, col)] &^= bit
+	for _, h := range HousesOf(row, col) {
+		s.house[h] &^= bit
+	}
+}
+
+func (s *counter) found() {
+	if s.solutions == 0 {
+		s.solution = s.m
+	}
+	s.solutions++
+}
+
+// digits placed in the row, col, block and extra houses of the cell as a bit mask
+func (s *counter) used(row, col int) int {
+	mask := s.row[row] | s.col[col] | s.blk[BlkOf(row, col)]
+	for _, h := range HousesOf(row, col) {
+		mask |= s.house[h]
+	}
+	return mask
+}
+
+// candidates of an empty cell as a bit mask
+func (s *counter) cands(row, col int) int {
+	mask := (1<<(N+1) - 2) &^ s.used(row, col)
+	if s.allowed[row][col] != 0 {
+		mask &= s.allowed[row][col]
+	}
+
+	if len(Constraints) > 0 {
+		for d := 1; d <= N; d++ {
+			if mask&(1<<d) != 0 && !ConstraintsValid(s.m, row, col, d) {
+				mask &^= 1 << d
+			}
+		}
+	}
+	return mask
+}
+
+func (s *counter) nextCell() (int, int, []int) {
+	row, col, mask, best := -1, -1, 0, N+1
+	for i := 0; i < N && best > 0; i++ {
+		for j := 0; j < N; j++ {
+			if s.m[i][j] != 0 {
+				continue
+			}
+			c := s.cands(i, j)
+			if n := bits.OnesCount(uint(c)); n < best {
+				row, col, mask, best = i, j, c, n
+				if n == 0 {
+					break
+				}
+			}
+		}
+	}
+
+	var digits []int
+	for d := 1; d <= N; d++ {
+		if mask&(1<<d) != 0 {
+			digits = append(digits, d)
+		}
+	}
+	return row, col, digits
+}
@@ -0,0 +1,152 @@
+package lib
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Extra houses used by sudoku variants in addition to rows, cols and blocks.
+// Like a row, each house has N cells which must hold the digits 1 to N exactly once.
+var Houses [][]Coord
+
+// Variant names and the extra houses or constraints they install
+var variants = map[string]func(){
+	"x":          func() { Houses = append(Houses, diagonalHouses()...) },
+	"diagonal":   func() { Houses = append(Houses, diagonalHouses()...) },
+	"windoku":    func() { Houses = append(Houses, windokuHouses()...) },
+	"hyper":      func() { Houses = append(Houses, windokuHouses()...) },
+	"centredot":  func() { Houses = append(Houses, centreDotHouses()...) },
+	"centerdot":  func() { Houses = append(Houses, centreDotHouses()...) },
+	"antiknight": func() { AddConstraint(moveConstraint{moves: knightMoves}) },
+	"antiking":   func() { AddConstraint(moveConstraint{moves: kingMoves}) },
+	"nonconsec":  func() { AddConstraint(nonConsecutive{}) },
+}
+
+// Enable the variants in a comma separated list, e.g. "x,windoku" or "antiknight,antiking"
+func SetVariants(list string) error {
+	ClearVariants()
+
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		fn, ok := variants[strings.ReplaceAll(name, "-", "")]
+		if !ok {
+			return fmt.Errorf("unknown variant %q", name)
+		}
+		fn()
+	}
+	return nil
+}
+
+// Remove all extra houses
+func ClearHouses() {
+	Houses = nil
+}
+
+// Remove all extra houses and constraints, i.e. back to classic sudoku
+func ClearVariants() {
+	ClearHouses()
+	ClearConstraints()
+}
+
+// the 2 main diagonals of Sudoku-X
+func diagonalHouses() [][]Coord {
+	var diag, anti []Coord
+
+	for i := 0; i < N; i++ {
+		diag = append(diag, Coord{Row: i, Col: i})
+		anti = append(anti, Coord{Row: i, Col: N - 1 - i})
+	}
+	return [][]Coord{diag, anti}
+}
+
+// the 4 shaded windows of Windoku (hyper sudoku)
+func windokuHouses() [][]Coord {
+	var houses [][]Coord
+
+	for _, startRow := range []int{1, 5} {
+		for _, startCol := range []int{1, 5} {
+			var h []Coord
+			for x := startRow; x < startRow+SQ; x++ {
+				for y := startCol; y < startCol+SQ; y++ {
+					h = append(h, Coord{Row: x, Col: y})
+				}
+			}
+			houses = append(houses, h)
+		}
+	}
+	return houses
+}
+
+// the centre cells of the N blocks
+func centreDotHouses() [][]Coord {
+	var h []Coord
+
+	for bi := 0; bi < SQ; bi++ {
+		for bj := 0; bj < SQ; bj++ {
+			h = append(h, Coord{Row: bi*SQ + SQ/2, Col: bj*SQ + SQ/2})
+		}
+	}
+	return [][]Coord{h}
+}
+
+// Indices of the extra houses that contain cell [row,col]
+func HousesOf(row, col int) []int {
+	var list []int
+
+	for h, house := range Houses {
+		for _, c := range house {
+			if c.Row == row && c.Col == col {
+				list = append(list, h)
+				break
+			}
+		}
+	}
+	return list
+}
+
+// Returns true if num is already placed in any extra house containing cell [row,col]
+func InHouses(m Intmat, row, col, num int) bool {
+	for _, h := range HousesOf(row, col) {
+		for _, c := range Houses[h] {
+			if m[c.Row][c.Col] == num {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// check extra house h of possibility matrix for the digit, excluding cell [row,col]
+func FindDigitInHouse(debug bool, mat2 Pmat, h, row, col, dig int) bool {
+	for _, c := range Houses[h] {
+		if mat2[c.Row][c.Col] != nil && !(c.Row == row && c.Col == col) {
+			if Contains(mat2[c.Row][c.Col], dig) {
+				if debug {
+					fmt.Printf("house %d contains digit %d\n", h, dig)
+				}
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// check extra house h of possibility matrix for any digit of the naked pair
+func FindDigitInHousePair(debug bool, mat2 Pmat, h, row, col, row2, col2 int, digits []int) bool {
+	for _, c := range Houses[h] {
+		x, y := c.Row, c.Col
+		if mat2[x][y] != nil && !(x == row && y == col) && !(x == row2 && y == col2) {
+			if ContainsMulti(mat2[x][y], digits) {
+				if debug {
+					fmt.Printf("Cell [%d,%d] contains digits of naked pair", x, y)
+				}
+				return true
+			}
+		}
+	}
+	return false
+}
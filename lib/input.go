@@ -0,0 +1,87 @@
+package lib
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Puzzle read from a file, with the line it came from
+type Puzzle struct {
+	Line int
+	Mat  Intmat
+}
+
+// Parse a puzzle of 81 cells in row order. Digits are clues, 0 . _ or * an empty cell.
+// White space is ignored.
+func ParseMat(s string) (Intmat, error) {
+	var m Intmat
+
+	k := 0
+	for _, ch := range s {
+		if unicode.IsSpace(ch) {
+			continue
+		}
+
+		var d int
+		switch {
+		case ch >= '1' && ch <= '0'+N:
+			d = int(ch - '0')
+		case ch == '0' || ch == '.' || ch == '_' || ch == '*':
+		default:
+			return m, fmt.Errorf("bad character %q in cell %d", ch, k+1)
+		}
+
+		if k < N*N {
+			m[k/N][k%N] = d
+		}
+		k++
+	}
+
+	if k != N*N {
+		return m, fmt.Errorf("expected %d cells but got %d", N*N, k)
+	}
+	return m, nil
+}
+
+// Read puzzles, one per line. Blank lines and comments from # to the end of the line are
+// skipped. Errors give the line number.
+func ReadPuzzles(r io.Reader) ([]Puzzle, error) {
+	var list []Puzzle
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		s := scanner.Text()
+		if k := strings.Index(s, "#"); k >= 0 {
+			s = s[:k]
+		}
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+
+		m, err := ParseMat(s)
+		if err != nil {
+			return list, fmt.Errorf("line %d: %v", n, err)
+		}
+		list = append(list, Puzzle{Line: n, Mat: m})
+	}
+	return list, scanner.Err()
+}
+
+// Read the puzzles of a file, see ReadPuzzles
+func ReadPuzzleFile(fname string) ([]Puzzle, error) {
+	f, err := os.Open(fname)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	list, err := ReadPuzzles(f)
+	if err != nil {
+		return list, fmt.Errorf("%s: %v", fname, err)
+	}
+	return list, nil
+}
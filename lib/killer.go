@@ -0,0 +1,395 @@
+package lib
+
+import (
+	"bufio"
+	"fmt"
+	"math/bits"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Cage of a killer sudoku. The digits in the cells add up to Sum and may not repeat.
+type Cage struct {
+	Sum   int
+	Cells []Coord
+}
+
+// Cages of the current killer sudoku
+var Cages []Cage
+
+// Install the cages of a killer sudoku. Besides the cages themselves, the 45 rule
+// adds virtual cages for the innies and outies of every row, col and block.
+func SetCages(cages []Cage) error {
+	var owner [N][N]int
+
+	for i := range owner {
+		for j := range owner[i] {
+			owner[i][j] = -1
+		}
+	}
+
+	for k, cg := range cages {
+		if len(cg.Cells) == 0 || len(cg.Cells) > N {
+			return fmt.Errorf("cage %d has %d cells", k+1, len(cg.Cells))
+		}
+		if cg.Sum < minSum(len(cg.Cells)) || cg.Sum > maxSum(len(cg.Cells)) {
+			return fmt.Errorf("cage %d: sum %d is impossible for %d cells", k+1, cg.Sum, len(cg.Cells))
+		}
+		for _, c := range cg.Cells {
+			if c.Row < 0 || c.Row >= N || c.Col < 0 || c.Col >= N {
+				return fmt.Errorf("cage %d: cell [%d,%d] is out of range", k+1, c.Row, c.Col)
+			}
+			if owner[c.Row][c.Col] >= 0 {
+				return fmt.Errorf("cage %d: cell [%d,%d] is already in cage %d", k+1, c.Row, c.Col, owner[c.Row][c.Col]+1)
+			}
+			owner[c.Row][c.Col] = k
+		}
+	}
+
+	Cages = cages
+	for _, cg := range cages {
+		AddConstraint(cg)
+	}
+	for _, cg := range rule45Cages(cages) {
+		AddConstraint(cg)
+	}
+	return nil
+}
+
+// Parse cage definitions. Each line holds the sum followed by the cells of the cage
+// in row/col notation starting from 1, e.g. "15 r1c1 r1c2 r2c1". Blank lines and
+// lines starting with # are skipped.
+func ParseCages(s string) ([]Cage, error) {
+	var cages []Cage
+
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		sum, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid cage sum %q", lineNo, fields[0])
+		}
+
+		cg := Cage{Sum: sum}
+		for _, f := range fields[1:] {
+			c, err := ParseCell(f)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", lineNo, err)
+			}
+			cg.Cells = append(cg.Cells, c)
+		}
+		if len(cg.Cells) == 0 {
+			return nil, fmt.Errorf("line %d: cage has no cells", lineNo)
+		}
+		cages = append(cages, cg)
+	}
+	return cages, nil
+}
+
+// Read a cage file and install the cages
+func ReadCages(fname string) error {
+	b, err := os.ReadFile(fname)
+	if err != nil {
+		return err
+	}
+
+	cages, err := ParseCages(string(b))
+	if err != nil {
+		return fmt.Errorf("%s: %v", fname, err)
+	}
+	return SetCages(cages)
+}
+
+// Parse a cell in row/col notation, e.g. "r3c5" is [2,4]
+func ParseCell(s string) (Coord, error) {
+	var r, c int
+
+	n, err := fmt.Sscanf(strings.ToLower(s), "r%1dc%1d", &r, &c)
+	if err != nil || n != 2 || r < 1 || r > N || c < 1 || c > N {
+		return Coord{}, fmt.Errorf("invalid cell %q", s)
+	}
+	return Coord{Row: r - 1, Col: c - 1}, nil
+}
+
+func (cg Cage) contains(row, col int) bool {
+	for _, c := range cg.Cells {
+		if c.Row == row && c.Col == col {
+			return true
+		}
+	}
+	return false
+}
+
+// Valid checks that num does not repeat in the cage and that the cage sum can still be reached
+func (cg Cage) Valid(m Intmat, row, col, num int) bool {
+	if !cg.contains(row, col) {
+		return true
+	}
+
+	var used uint
+	sum := num
+	empty := 0
+	used |= 1 << num
+
+	for _, c := range cg.Cells {
+		if c.Row == row && c.Col == col {
+			continue
+		}
+		v := m[c.Row][c.Col]
+		if v == 0 {
+			empty++
+		} else if used&(1<<v) != 0 {
+			return false
+		} else {
+			used |= 1 << v
+			sum += v
+		}
+	}
+
+	if empty == 0 {
+		return sum == cg.Sum
+	}
+	lo, hi := unusedSums(used, empty)
+	return sum+lo <= cg.Sum && sum+hi >= cg.Sum
+}
+
+// Prune keeps only the candidates which appear in a combination of distinct digits
+// adding up to the cage sum that can be assigned to the empty cells.
+func (cg Cage) Prune(m Intmat, m2 Pmat) []Elim {
+	var (
+		placed, placedSum int
+		empty             []Coord
+		elims             []Elim
+	)
+
+	for _, c := range cg.Cells {
+		if v := m[c.Row][c.Col]; v != 0 {
+			placed |= 1 << v
+			placedSum += v
+		} else {
+			empty = append(empty, c)
+		}
+	}
+	if len(empty) == 0 {
+		return nil
+	}
+
+	cands := make([]int, len(empty))
+	for k, c := range empty {
+		for _, d := range m2[c.Row][c.Col] {
+			cands[k] |= 1 << d
+		}
+	}
+
+	supported := make([]int, len(empty))
+	for _, combo := range combinations(cg.Sum-placedSum, len(empty)) {
+		if combo&placed != 0 {
+			continue
+		}
+		for k := range empty {
+			for d := 1; d <= N; d++ {
+				bit := 1 << d
+				if combo&bit == 0 || cands[k]&bit == 0 || supported[k]&bit != 0 {
+					continue
+				}
+				if canAssign(cands, k, combo&^bit) {
+					supported[k] |= bit
+				}
+			}
+		}
+	}
+
+	for k, c := range empty {
+		for _, d := range m2[c.Row][c.Col] {
+			if supported[k]&(1<<d) == 0 {
+				elims = append(elims, Elim{Row: c.Row, Col: c.Col, Dig: d})
+			}
+		}
+	}
+	return elims
+}
+
+// Returns true if the digits in mask can be given one each to the cells other than skip
+func canAssign(cands []int, skip, mask int) bool {
+	if mask == 0 {
+		return true
+	}
+
+	d := bits.TrailingZeros(uint(mask))
+	for k := range cands {
+		if k != skip && cands[k]&(1<<d) != 0 {
+			rest := append([]int{}, cands...)
+			rest[k] = 0
+			if canAssign(rest, skip, mask&^(1<<d)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// All sets of cnt distinct digits adding up to sum, as bit masks of the digits
+func combinations(sum, cnt int) []int {
+	var list []int
+
+	for mask := 0; mask < 1<<N; mask++ {
+		if bits.OnesCount(uint(mask)) != cnt {
+			continue
+		}
+		s := 0
+		for d := 1; d <= N; d++ {
+			if mask&(1<<(d-1)) != 0 {
+				s += d
+			}
+		}
+		if s == sum {
+			list = append(list, mask<<1) // bit d for digit d
+		}
+	}
+	return list
+}
+
+// smallest and largest sums of cnt distinct digits not in used
+func unusedSums(used uint, cnt int) (int, int) {
+	lo, hi := 0, 0
+
+	for d, k := 1, 0; d <= N && k < cnt; d++ {
+		if used&(1<<d) == 0 {
+			lo += d
+			k++
+		}
+	}
+	for d, k := N, 0; d >= 1 && k < cnt; d-- {
+		if used&(1<<d) == 0 {
+			hi += d
+			k++
+		}
+	}
+	return lo, hi
+}
+
+func minSum(cnt int) int {
+	lo, _ := unusedSums(0, cnt)
+	return lo
+}
+
+func maxSum(cnt int) int {
+	_, hi := unusedSums(0, cnt)
+	return hi
+}
+
+// 45 rule: the digits of a row, col or block add up to 45. The cells of a house not
+// covered by cages lying wholly inside it (innies) add up to 45 less those cages.
+// If the cages overlapping a house cover it entirely, the cells sticking out of it
+// (outies) add up to those cages less 45. Such sets of cells form virtual cages as
+// long as their digits cannot repeat, i.e. they lie in a single house.
+func rule45Cages(cages []Cage) []Cage {
+	var virtual []Cage
+	const houseSum = N * (N + 1) / 2
+
+	for _, house := range standardHouses() {
+		inHouse := map[Coord]bool{}
+		for _, c := range house {
+			inHouse[c] = true
+		}
+
+		covered := map[Coord]bool{}
+		var outies []Coord
+		innerSum, overlapSum := 0, 0
+
+		for _, cg := range cages {
+			inside, outside := 0, []Coord{}
+			for _, c := range cg.Cells {
+				if inHouse[c] {
+					inside++
+				} else {
+					outside = append(outside, c)
+				}
+			}
+			if inside == 0 {
+				continue
+			}
+			for _, c := range cg.Cells {
+				if inHouse[c] {
+					covered[c] = true
+				}
+			}
+			overlapSum += cg.Sum
+			if len(outside) == 0 {
+				innerSum += cg.Sum
+			} else {
+				outies = append(outies, outside...)
+			}
+		}
+
+		// innies
+		var innies []Coord
+		for _, c := range house {
+			if !isCovered(c, cages, inHouse) {
+				innies = append(innies, c)
+			}
+		}
+		if len(innies) > 0 && len(innies) < N {
+			virtual = append(virtual, Cage{Sum: houseSum - innerSum, Cells: innies})
+		}
+
+		// outies
+		if len(covered) == N && len(outies) > 0 && len(outies) < N && inOneHouse(outies) {
+			virtual = append(virtual, Cage{Sum: overlapSum - houseSum, Cells: outies})
+		}
+	}
+	return virtual
+}
+
+// Returns true if the cell lies in a cage wholly inside the house
+func isCovered(c Coord, cages []Cage, inHouse map[Coord]bool) bool {
+	for _, cg := range cages {
+		if !cg.contains(c.Row, c.Col) {
+			continue
+		}
+		for _, x := range cg.Cells {
+			if !inHouse[x] {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// Returns true if all the cells share a row, col or block
+func inOneHouse(cells []Coord) bool {
+	sameRow, sameCol, sameBlk := true, true, true
+
+	for _, c := range cells[1:] {
+		sameRow = sameRow && c.Row == cells[0].Row
+		sameCol = sameCol && c.Col == cells[0].Col
+		sameBlk = sameBlk && BlkOf(c.Row, c.Col) == BlkOf(cells[0].Row, cells[0].Col)
+	}
+	return sameRow || sameCol || sameBlk
+}
+
+// The rows, cols and blocks of the grid
+func standardHouses() [][]Coord {
+	var houses [][]Coord
+
+	for i := 0; i < N; i++ {
+		var row, col []Coord
+		for j := 0; j < N; j++ {
+			row = append(row, Coord{Row: i, Col: j})
+			col = append(col, Coord{Row: j, Col: i})
+		}
+		houses = append(houses, row, col)
+	}
+	for b := 0; b < N; b++ {
+		houses = append(houses, BlkCells(b))
+	}
+	return houses
+}
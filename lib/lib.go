@@ -81,19 +81,11 @@ func GetArrForCol(m Intmat, j int) []int {
 }
 
 func GetArrForSqu(m Intmat, i, j int) []int {
-	var (
-		startRow, startCol int
-		arrSq              []int
-	)
-
-	startRow = i / SQ * SQ
-	startCol = j / SQ * SQ
+	var arrSq []int
 
-	for x := startRow; x < startRow+SQ; x++ {
-		for y := startCol; y < startCol+SQ; y++ {
-			if m[x][y] != 0 {
-				arrSq = append(arrSq, m[x][y])
-			}
+	for _, c := range BlkCells(BlkOf(i, j)) {
+		if m[c.Row][c.Col] != 0 {
+			arrSq = append(arrSq, m[c.Row][c.Col])
 		}
 	}
 	return arrSq
@@ -125,13 +117,8 @@ func GetColOfPossibleMat(mat2 Pmat, col int) [][]int {
 func GetBlkOfPossibleMat(mat2 Pmat, row, col int) [][]int {
 	var m [][]int
 
-	startRow := row / SQ * SQ
-	startCol := col / SQ * SQ
-
-	for x := startRow; x < startRow+SQ; x++ {
-		for y := startCol; y < startCol+SQ; y++ {
-			m = append(m, mat2[x][y])
-		}
+	for _, c := range BlkCells(BlkOf(row, col)) {
+		m = append(m, mat2[c.Row][c.Col])
 	}
 	return m
 }
@@ -241,21 +228,6 @@ func CountElemPosMat(m Pmat) int {
 	return count
 }
 
-func ReadInput() string {
-	var (
-		l int
-		s string
-	)
-	fmt.Println("Enter sudoku string (. rep empty square)")
-	fmt.Scanf("%s\n", &s)
-	l = len(s)
-	fmt.Printf("Length: %d\n", l)
-	if l != 81 {
-		panic("Expected 81")
-	}
-	return s
-}
-
 // ****************************************** start of find/erase fns ******************************************
 
 func InRow(m Intmat, row, num int) bool {
@@ -277,21 +249,17 @@ func InCol(m Intmat, col, num int) bool {
 }
 
 func InSqu(m Intmat, row, col, num int) bool {
-	startRow := row / SQ * SQ
-	startCol := col / SQ * SQ
-
-	for x := startRow; x < startRow+SQ; x++ {
-		for y := startCol; y < startCol+SQ; y++ {
-			if m[x][y] == num {
-				return true
-			}
+	for _, c := range BlkCells(BlkOf(row, col)) {
+		if m[c.Row][c.Col] == num {
+			return true
 		}
 	}
 	return false
 }
 
 func IsSafe(m Intmat, row, col, num int) bool {
-	if !InRow(m, row, num) && !InCol(m, col, num) && !InSqu(m, row, col, num) {
+	if !InRow(m, row, num) && !InCol(m, col, num) && !InSqu(m, row, col, num) && !InHouses(m, row, col, num) &&
+		ConstraintsValid(m, row, col, num) {
 		return true
 	}
 	return false
@@ -307,6 +275,7 @@ func CheckSums(m Intmat) bool {
 		rowSums []int
 		colSums []int
 		sqSums  []int
+		hseSums []int
 		success bool
 	)
 	success = false
@@ -330,20 +299,27 @@ func CheckSums(m Intmat) bool {
 
 	}
 
-	for i := 0; i < SQ; i++ {
-		for j := 0; j < SQ; j++ {
-			sSum := 0
-			startrow := i * SQ
-			startcol := j * SQ
-			for x := startrow; x < startrow+SQ; x++ {
-				for y := startcol; y < startcol+SQ; y++ {
-					val = m[x][y]
-					if val > 0 {
-						sSum += val
-					}
-				}
+	for b := 0; b < N; b++ {
+		sSum := 0
+		for _, c := range BlkCells(b) {
+			val = m[c.Row][c.Col]
+			if val > 0 {
+				sSum += val
 			}
-			sqSums = append(sqSums, sSum)
+		}
+		sqSums = append(sqSums, sSum)
+	}
+
+	// every extra house of a variant must also sum to 45
+	houseOK := true
+	for _, h := range Houses {
+		hSum := 0
+		for _, c := range h {
+			hSum += m[c.Row][c.Col]
+		}
+		hseSums = append(hseSums, hSum)
+		if hSum != totalSum/N {
+			houseOK = false
 		}
 	}
 
@@ -351,8 +327,17 @@ func CheckSums(m Intmat) bool {
 	fmt.Printf("Row sums: %2v\n", rowSums)
 	fmt.Printf("Col sums: %2v\n", colSums)
 	fmt.Printf("Squ sums: %2v\n", sqSums)
+	if len(Houses) > 0 {
+		fmt.Printf("Hse sums: %2v\n", hseSums)
+	}
+
+	// variants with constraints, e.g. killer cages
+	if len(Constraints) > 0 {
+		fmt.Printf("Constraints hold: %t\n", ConstraintsHold(m))
+		houseOK = houseOK && ConstraintsHold(m)
+	}
 
-	if sumArr(rowSums) == totalSum && sumArr(colSums) == totalSum && sumArr(sqSums) == totalSum {
+	if sumArr(rowSums) == totalSum && sumArr(colSums) == totalSum && sumArr(sqSums) == totalSum && houseOK {
 		color.New(color.FgLightBlue, color.OpBold).Println("Finished!")
 		success = true
 	}
@@ -368,6 +353,10 @@ func sumArr(arr []int) int {
 }
 
 func PrintPossibleMat(m Pmat) {
+	if GridStyle != GridLegacy {
+		printCandidates(m)
+		return
+	}
 	fmt.Println("-------------------------------------------------------------------------------------------------------------------------------------------------------------------------------------------------------")
 
 	for i := 0; i < N; i++ {
@@ -386,15 +375,25 @@ func arr2String(a []int, delim string) string {
 }
 
 func PrintSudoku(m Intmat) {
-	var sqi, sqj int
+	switch {
+	case GridStyle == GridLine:
+		fmt.Println(MatToString(m))
+		return
+	case len(Relations) > 0 || len(Parities) > 0:
+		printSudokuMarks(m) // the marks between the cells have no place in the box style
+		return
+	case GridStyle == GridBox:
+		printBoxGrid(m)
+		return
+	}
+
 	for i := 0; i < N; i++ {
-		sqi = (i / SQ) % 2
 		for j := 0; j < N; j++ {
-			sqj = (j / SQ) % 2
-			if (sqi == 0 && sqj == 1) || (sqi == 1 && sqj == 0) {
-				color.LightBlue.Printf("%d ", m[i][j])
+			// alternate colours between neighbouring blocks (or jigsaw regions)
+			if BlkOf(i, j)%2 == 1 {
+				color.LightBlue.Printf("%s ", CellText(m[i][j]))
 			} else {
-				color.LightGreen.Printf("%d ", m[i][j])
+				color.LightGreen.Printf("%s ", CellText(m[i][j]))
 			}
 		}
 		fmt.Println()
@@ -462,23 +461,19 @@ func FindDigitInBlk(debug bool, mat2 Pmat, row, col, dig int) bool {
 		fmt.Println("In findDigitInBlk...")
 	}
 
-	startRow := row / SQ * SQ
-	startCol := col / SQ * SQ
-
-	for x := startRow; x < startRow+SQ; x++ {
-		for y := startCol; y < startCol+SQ; y++ {
-			if mat2[x][y] != nil && !(x == row && y == col) {
-				if debug {
-					color.White.Printf("Cell [%d][%d] = %v\n", x, y, mat2[x][y])
-				}
+	for _, c := range BlkCells(BlkOf(row, col)) {
+		x, y := c.Row, c.Col
+		if mat2[x][y] != nil && !(x == row && y == col) {
+			if debug {
+				color.White.Printf("Cell [%d][%d] = %v\n", x, y, mat2[x][y])
+			}
 
-				if Contains(mat2[x][y], dig) {
+			if Contains(mat2[x][y], dig) {
 
-					if debug {
-						fmt.Printf("blk [%d,%d] contains digit %d\n", x/SQ, y/SQ, dig)
-					}
-					return true
+				if debug {
+					fmt.Printf("blk %d contains digit %d\n", BlkOf(x, y), dig)
 				}
+				return true
 			}
 		}
 	}
@@ -532,22 +527,18 @@ func FindDigitInColPair(debug bool, mat2 Pmat, row, col, row2 int, digits []int)
 
 // check block of possibility matrix
 func FindDigitInBlkPair(debug bool, mat2 Pmat, row, col, row2, col2 int, digits []int) bool {
-	startRow := row / SQ * SQ
-	startCol := col / SQ * SQ
+	for _, c := range BlkCells(BlkOf(row, col)) {
+		x, y := c.Row, c.Col
+		if mat2[x][y] != nil && !(x == row && y == col) && !(x == row2 && y == col2) {
+			if debug {
+				fmt.Printf("Cell [%d][%d] = %v\n", x, y, mat2[x][y])
+			}
+			if ContainsMulti(mat2[x][y], digits) {
 
-	for x := startRow; x < startRow+SQ; x++ {
-		for y := startCol; y < startCol+SQ; y++ {
-			if mat2[x][y] != nil && !(x == row && y == col) && !(x == row2 && y == col2) {
 				if debug {
-					fmt.Printf("Cell [%d][%d] = %v\n", x, y, mat2[x][y])
-				}
-				if ContainsMulti(mat2[x][y], digits) {
-
-					if debug {
-						fmt.Printf("Cell [%d,%d] contains digits of naked pair", x, y)
-					}
-					return true
+					fmt.Printf("Cell [%d,%d] contains digits of naked pair", x, y)
 				}
+				return true
 			}
 		}
 	}
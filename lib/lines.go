@@ -0,0 +1,525 @@
+package lib
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ConstraintParser builds a constraint from the fields which follow its kind on a line
+type ConstraintParser func(args []string) (Constraint, error)
+
+// Parsers of the constraint kinds which may appear in a constraint file
+var constraintKinds = map[string]ConstraintParser{}
+
+// Register a constraint kind for ParseConstraints. Registering a kind again replaces it.
+func RegisterConstraint(kind string, p ConstraintParser) {
+	constraintKinds[strings.ToLower(kind)] = p
+}
+
+func init() {
+	RegisterConstraint("thermo", parseThermo)
+	RegisterConstraint("arrow", parseArrow)
+	RegisterConstraint("sandwich", parseSandwich)
+	RegisterConstraint("littlekiller", parseLittleKiller)
+}
+
+// Parse a constraint file. Each line holds a registered kind followed by its arguments,
+// cells in row/col notation starting from 1:
+//
+//	thermo r1c1 r1c2 r1c3          digits increase from the bulb r1c1
+//	arrow r5c5 r4c4 r3c3           the circle r5c5 holds the sum of the arrow
+//	sandwich row 3 15              digits between 1 and 9 in row 3 add up to 15
+//	littlekiller 22 r1c2 r2c3 ...  digits on the diagonal add up to 22
+//
+// Blank lines and lines starting with # are skipped.
+func ParseConstraints(s string) ([]Constraint, error) {
+	var list []Constraint
+
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		parse, ok := constraintKinds[strings.ToLower(fields[0])]
+		if !ok {
+			return nil, fmt.Errorf("line %d: unknown constraint %q", lineNo, fields[0])
+		}
+		c, err := parse(fields[1:])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNo, err)
+		}
+		list = append(list, c)
+	}
+	return list, nil
+}
+
+// Read a constraint file and install the constraints
+func ReadConstraints(fname string) error {
+	b, err := os.ReadFile(fname)
+	if err != nil {
+		return err
+	}
+
+	list, err := ParseConstraints(string(b))
+	if err != nil {
+		return fmt.Errorf("%s: %v", fname, err)
+	}
+	for _, c := range list {
+		AddConstraint(c)
+	}
+	return nil
+}
+
+// Parse a list of cells in row/col notation
+func parseCells(args []string) ([]Coord, error) {
+	var cells []Coord
+
+	for _, f := range args {
+		c, err := ParseCell(f)
+		if err != nil {
+			return nil, err
+		}
+		cells = append(cells, c)
+	}
+	return cells, nil
+}
+
+// Returns true if every cell of the path touches the next one, diagonals included
+func isPath(cells []Coord) bool {
+	for k := 1; k < len(cells); k++ {
+		dr, dc := cells[k].Row-cells[k-1].Row, cells[k].Col-cells[k-1].Col
+		if dr < -1 || dr > 1 || dc < -1 || dc > 1 || dr == 0 && dc == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Index of cell [row,col] in the list, -1 if absent
+func indexOf(cells []Coord, row, col int) int {
+	for k, c := range cells {
+		if c.Row == row && c.Col == col {
+			return k
+		}
+	}
+	return -1
+}
+
+// Smallest and largest value of a cell, i.e. of its placed digit or its candidates
+func cellBounds(m Intmat, m2 Pmat, c Coord) (int, int) {
+	vals := cellValues(m, m2, c)
+	if len(vals) == 0 {
+		return N + 1, 0
+	}
+
+	lo, hi := vals[0], vals[0]
+	for _, v := range vals[1:] {
+		lo = minInt(lo, v)
+		hi = maxInt(hi, v)
+	}
+	return lo, hi
+}
+
+// Sum of the smallest and largest values of the cells, except the one at index skip
+func sumBounds(m Intmat, m2 Pmat, cells []Coord, skip int) (int, int) {
+	lo, hi := 0, 0
+
+	for k, c := range cells {
+		if k != skip {
+			l, h := cellBounds(m, m2, c)
+			lo += l
+			hi += h
+		}
+	}
+	return lo, hi
+}
+
+// Erase the candidates of the empty cells which fall outside [lo[k],hi[k]]
+func pruneRange(m Intmat, m2 Pmat, cells []Coord, lo, hi []int) []Elim {
+	var elims []Elim
+
+	for k, c := range cells {
+		if m[c.Row][c.Col] != 0 {
+			continue
+		}
+		for _, d := range m2[c.Row][c.Col] {
+			if d < lo[k] || d > hi[k] {
+				elims = append(elims, Elim{Row: c.Row, Col: c.Col, Dig: d})
+			}
+		}
+	}
+	return elims
+}
+
+// *******************************************************************************************************
+// *                                              thermometer                                              *
+// *******************************************************************************************************
+
+// Thermometer: digits strictly increase from the bulb (first cell) to the tip
+type Thermo struct {
+	Cells []Coord
+}
+
+func parseThermo(args []string) (Constraint, error) {
+	cells, err := parseCells(args)
+	if err != nil {
+		return nil, err
+	}
+	if len(cells) < 2 || len(cells) > N {
+		return nil, fmt.Errorf("thermometer has %d cells", len(cells))
+	}
+	if !isPath(cells) {
+		return nil, fmt.Errorf("thermometer cells are not connected")
+	}
+	return Thermo{Cells: cells}, nil
+}
+
+func (th Thermo) Valid(m Intmat, row, col, num int) bool {
+	k := indexOf(th.Cells, row, col)
+	if k < 0 {
+		return true
+	}
+	// room for the cells below and above
+	if num < k+1 || num > N-(len(th.Cells)-1-k) {
+		return false
+	}
+
+	for i, c := range th.Cells {
+		v := m[c.Row][c.Col]
+		if v == 0 || i == k {
+			continue
+		}
+		if i < k && num-v < k-i || i > k && v-num < i-k {
+			return false
+		}
+	}
+	return true
+}
+
+// Prune keeps each cell above the smallest value of the cell before it and below the
+// largest value of the cell after it
+func (th Thermo) Prune(m Intmat, m2 Pmat) []Elim {
+	cnt := len(th.Cells)
+	lo := make([]int, cnt)
+	hi := make([]int, cnt)
+
+	for k, c := range th.Cells {
+		lo[k], hi[k] = cellBounds(m, m2, c)
+	}
+	for k := 1; k < cnt; k++ {
+		lo[k] = maxInt(lo[k], lo[k-1]+1)
+	}
+	for k := cnt - 2; k >= 0; k-- {
+		hi[k] = minInt(hi[k], hi[k+1]-1)
+	}
+	return pruneRange(m, m2, th.Cells, lo, hi)
+}
+
+// *******************************************************************************************************
+// *                                                 arrow                                                 *
+// *******************************************************************************************************
+
+// Arrow: the digit in the circle equals the sum of the digits along the arrow.
+// Digits may repeat on the arrow unless a row, col or block forbids it.
+type Arrow struct {
+	Circle Coord
+	Cells  []Coord
+}
+
+func parseArrow(args []string) (Constraint, error) {
+	cells, err := parseCells(args)
+	if err != nil {
+		return nil, err
+	}
+	if len(cells) < 2 {
+		return nil, fmt.Errorf("arrow needs a circle and at least 1 cell")
+	}
+	if !isPath(cells) {
+		return nil, fmt.Errorf("arrow cells are not connected")
+	}
+	return Arrow{Circle: cells[0], Cells: cells[1:]}, nil
+}
+
+func (ar Arrow) Valid(m Intmat, row, col, num int) bool {
+	k := indexOf(ar.Cells, row, col)
+	isCircle := ar.Circle.Row == row && ar.Circle.Col == col
+	if k < 0 && !isCircle {
+		return true
+	}
+
+	circle := m[ar.Circle.Row][ar.Circle.Col]
+	if isCircle {
+		circle = num
+	}
+
+	sum, empty := 0, 0
+	for i, c := range ar.Cells {
+		v := m[c.Row][c.Col]
+		if i == k {
+			v = num
+		}
+		if v == 0 {
+			empty++
+		}
+		sum += v
+	}
+
+	if circle == 0 {
+		return sum+empty <= N
+	}
+	return sum+empty <= circle && sum+empty*N >= circle
+}
+
+// Prune keeps the circle within the bounds of the arrow sum and each arrow cell
+// within the circle less the bounds of the rest of the arrow
+func (ar Arrow) Prune(m Intmat, m2 Pmat) []Elim {
+	cLo, cHi := cellBounds(m, m2, ar.Circle)
+	sLo, sHi := sumBounds(m, m2, ar.Cells, -1)
+
+	elims := pruneRange(m, m2, []Coord{ar.Circle}, []int{sLo}, []int{sHi})
+
+	lo := make([]int, len(ar.Cells))
+	hi := make([]int, len(ar.Cells))
+	for k := range ar.Cells {
+		rLo, rHi := sumBounds(m, m2, ar.Cells, k)
+		lo[k], hi[k] = cLo-rHi, cHi-rLo
+	}
+	return append(elims, pruneRange(m, m2, ar.Cells, lo, hi)...)
+}
+
+// *******************************************************************************************************
+// *                                               sandwich                                                *
+// *******************************************************************************************************
+
+// Sandwich: the digits between 1 and N in a row or col add up to Sum
+type Sandwich struct {
+	Cells []Coord
+	Sum   int
+}
+
+func parseSandwich(args []string) (Constraint, error) {
+	if len(args) != 3 {
+		return nil, fmt.Errorf("expected row|col, index and sum")
+	}
+
+	idx, err := strconv.Atoi(args[1])
+	if err != nil || idx < 1 || idx > N {
+		return nil, fmt.Errorf("invalid index %q", args[1])
+	}
+	sum, err := strconv.Atoi(args[2])
+	if err != nil || sum < 0 || sum > N*(N+1)/2-1-N {
+		return nil, fmt.Errorf("invalid sandwich sum %q", args[2])
+	}
+
+	var cells []Coord
+	for j := 0; j < N; j++ {
+		switch strings.ToLower(args[0]) {
+		case "row":
+			cells = append(cells, Coord{Row: idx - 1, Col: j})
+		case "col":
+			cells = append(cells, Coord{Row: j, Col: idx - 1})
+		default:
+			return nil, fmt.Errorf("expected row or col but got %q", args[0])
+		}
+	}
+	return Sandwich{Cells: cells, Sum: sum}, nil
+}
+
+// Returns true if the crusts at positions p1 and p9 can hold Sum between them
+func (sw Sandwich) fits(m Intmat, m2 Pmat, p1, p9 int) bool {
+	a, b := minInt(p1, p9), maxInt(p1, p9)
+	gap := b - a - 1
+
+	lo, hi := 0, 0
+	for k := a + 1; k < b; k++ {
+		l, h := cellBounds(m, m2, sw.Cells[k])
+		lo += maxInt(l, 2)
+		hi += minInt(h, N-1)
+	}
+	// distinct digits between the crusts
+	dLo, dHi := unusedSums(1<<1|1<<N, gap)
+	return maxInt(lo, dLo) <= sw.Sum && minInt(hi, dHi) >= sw.Sum
+}
+
+func (sw Sandwich) Valid(m Intmat, row, col, num int) bool {
+	k := indexOf(sw.Cells, row, col)
+	if k < 0 {
+		return true
+	}
+
+	vals := make([]int, N)
+	p1, p9 := -1, -1
+	for i, c := range sw.Cells {
+		vals[i] = m[c.Row][c.Col]
+		if i == k {
+			vals[i] = num
+		}
+		switch vals[i] {
+		case 1:
+			p1 = i
+		case N:
+			p9 = i
+		}
+	}
+	if p1 < 0 || p9 < 0 {
+		return true
+	}
+
+	a, b := minInt(p1, p9), maxInt(p1, p9)
+	sum, empty := 0, 0
+	for i := a + 1; i < b; i++ {
+		if vals[i] == 0 {
+			empty++
+		}
+		sum += vals[i]
+	}
+	return sum+empty*2 <= sw.Sum && sum+empty*(N-1) >= sw.Sum
+}
+
+// Prune tries every position of the 1 and the N. A candidate survives if some
+// placement of the crusts which can hold the sum allows it.
+func (sw Sandwich) Prune(m Intmat, m2 Pmat) []Elim {
+	var (
+		elims     []Elim
+		supported [N]int // bit d set if digit d survives in cell k
+	)
+
+	has := func(k, d int) bool {
+		return Contains(cellValues(m, m2, sw.Cells[k]), d)
+	}
+
+	for p1 := 0; p1 < N; p1++ {
+		for p9 := 0; p9 < N; p9++ {
+			if p1 == p9 || !has(p1, 1) || !has(p9, N) || !sw.fits(m, m2, p1, p9) {
+				continue
+			}
+
+			a, b := minInt(p1, p9), maxInt(p1, p9)
+			supported[p1] |= 1 << 1
+			supported[p9] |= 1 << N
+			for k := range sw.Cells {
+				if k == p1 || k == p9 {
+					continue
+				}
+				if k < a || k > b {
+					supported[k] |= (1<<(N+1) - 1) &^ (1<<1 | 1<<N)
+					continue
+				}
+				rLo, rHi := 0, 0
+				for i := a + 1; i < b; i++ {
+					if i != k {
+						l, h := cellBounds(m, m2, sw.Cells[i])
+						rLo += maxInt(l, 2)
+						rHi += minInt(h, N-1)
+					}
+				}
+				for d := 2; d < N; d++ {
+					if d >= sw.Sum-rHi && d <= sw.Sum-rLo {
+						supported[k] |= 1 << d
+					}
+				}
+			}
+		}
+	}
+
+	for k, c := range sw.Cells {
+		if m[c.Row][c.Col] != 0 {
+			continue
+		}
+		for _, d := range m2[c.Row][c.Col] {
+			if supported[k]&(1<<d) == 0 {
+				elims = append(elims, Elim{Row: c.Row, Col: c.Col, Dig: d})
+			}
+		}
+	}
+	return elims
+}
+
+// *******************************************************************************************************
+// *                                             little killer                                             *
+// *******************************************************************************************************
+
+// Little killer: the digits along a diagonal add up to Sum. Digits may repeat.
+type LittleKiller struct {
+	Sum   int
+	Cells []Coord
+}
+
+func parseLittleKiller(args []string) (Constraint, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("expected sum and cells")
+	}
+
+	sum, err := strconv.Atoi(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid little killer sum %q", args[0])
+	}
+	cells, err := parseCells(args[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	for k := 1; k < len(cells); k++ {
+		dr, dc := cells[k].Row-cells[0].Row, cells[k].Col-cells[0].Col
+		if dr != k && dr != -k || dc != k && dc != -k || dr*(cells[1].Col-cells[0].Col) != dc*(cells[1].Row-cells[0].Row) {
+			return nil, fmt.Errorf("little killer cells are not on a diagonal")
+		}
+	}
+	if sum < len(cells) || sum > len(cells)*N {
+		return nil, fmt.Errorf("sum %d is impossible for %d cells", sum, len(cells))
+	}
+	return LittleKiller{Sum: sum, Cells: cells}, nil
+}
+
+func (lk LittleKiller) Valid(m Intmat, row, col, num int) bool {
+	k := indexOf(lk.Cells, row, col)
+	if k < 0 {
+		return true
+	}
+
+	sum, empty := 0, 0
+	for i, c := range lk.Cells {
+		v := m[c.Row][c.Col]
+		if i == k {
+			v = num
+		}
+		if v == 0 {
+			empty++
+		}
+		sum += v
+	}
+	return sum+empty <= lk.Sum && sum+empty*N >= lk.Sum
+}
+
+// Prune keeps each cell within the sum less the bounds of the rest of the diagonal
+func (lk LittleKiller) Prune(m Intmat, m2 Pmat) []Elim {
+	lo := make([]int, len(lk.Cells))
+	hi := make([]int, len(lk.Cells))
+
+	for k := range lk.Cells {
+		rLo, rHi := sumBounds(m, m2, lk.Cells, k)
+		lo[k], hi[k] = lk.Sum-rHi, lk.Sum-rLo
+	}
+	return pruneRange(m, m2, lk.Cells, lo, hi)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,78 @@
+package lib
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Clues whose removal on its own keeps the solution unique
+func RedundantClues(m Intmat) []Coord {
+	var list []Coord
+
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			if m[i][j] == 0 {
+				continue
+			}
+			v := m[i][j]
+			m[i][j] = 0
+			if CountSolutions(m, 2) == 1 {
+				list = append(list, Coord{Row: i, Col: j})
+			}
+			m[i][j] = v
+		}
+	}
+	return list
+}
+
+// Remove clues while the solution stays unique, giving a minimal puzzle in which every
+// clue is needed. With a symmetry, clues are removed together with their symmetric
+// cells so every remaining group is needed. Clues are tried in row order, or in random
+// order if r is set.
+func Minimize(m Intmat, sym string, r *rand.Rand) (Intmat, error) {
+	if sym == "" {
+		sym = SymNone
+	}
+	if err := CheckSymmetry(sym); err != nil {
+		return m, err
+	}
+	if !HasSymmetry(m, sym) {
+		return m, fmt.Errorf("puzzle does not have %s symmetry", sym)
+	}
+	if cnt := CountSolutions(m, 2); cnt != 1 {
+		return m, fmt.Errorf("puzzle has %s", SolutionsText(cnt))
+	}
+
+	order := make([]int, nsize)
+	for k := range order {
+		order[k] = k
+	}
+	if r != nil {
+		order = r.Perm(nsize)
+	}
+
+	// Removing more clues never makes a puzzle unique again, so a clue which is needed
+	// now is needed in the end and one pass is enough.
+	for _, p := range order {
+		if m[p/N][p%N] == 0 {
+			continue
+		}
+
+		saved := m
+		for _, c := range SymmetricCells(sym, p/N, p%N) {
+			m[c.Row][c.Col] = 0
+		}
+		if CountSolutions(m, 2) != 1 {
+			m = saved
+		}
+	}
+	return m, nil
+}
+
+// Text for a count of solutions other than one
+func SolutionsText(cnt int) string {
+	if cnt == 0 {
+		return "no solution"
+	}
+	return "more than one solution"
+}
@@ -0,0 +1,393 @@
+package lib
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+// MultiGrid is a puzzle of several N x N grids which share blocks, e.g. Samurai.
+// Cells holds the combined layout, 0 for an empty cell and -1 where there is no cell.
+type MultiGrid struct {
+	Offsets    []Coord // top left cell of each grid in the combined layout
+	Rows, Cols int
+	Cells      [][]int
+	gridsOf    [][][]int // grids containing each cell
+}
+
+// Grid offsets of the well known layouts
+var multiLayouts = map[string][]Coord{
+	"samurai":   {{Row: 0, Col: 0}, {Row: 0, Col: 12}, {Row: 6, Col: 6}, {Row: 12, Col: 0}, {Row: 12, Col: 12}},
+	"twin":      {{Row: 0, Col: 0}, {Row: 6, Col: 6}},
+	"butterfly": {{Row: 0, Col: 0}, {Row: 0, Col: 3}, {Row: 3, Col: 0}, {Row: 3, Col: 3}},
+}
+
+// Create an empty multi-grid. Grids may only overlap by whole blocks.
+func NewMultiGrid(offsets []Coord) (*MultiGrid, error) {
+	if len(offsets) == 0 {
+		return nil, fmt.Errorf("no grids")
+	}
+
+	mg := &MultiGrid{Offsets: offsets}
+	for g, o := range offsets {
+		if o.Row < 0 || o.Col < 0 {
+			return nil, fmt.Errorf("grid %d: negative offset [%d,%d]", g+1, o.Row, o.Col)
+		}
+		for h, o2 := range offsets[:g] {
+			dr, dc := o.Row-o2.Row, o.Col-o2.Col
+			overlap := dr > -N && dr < N && dc > -N && dc < N
+			if overlap && (dr%SQ != 0 || dc%SQ != 0) {
+				return nil, fmt.Errorf("grids %d and %d do not overlap by whole blocks", h+1, g+1)
+			}
+			if dr == 0 && dc == 0 {
+				return nil, fmt.Errorf("grids %d and %d have the same offset", h+1, g+1)
+			}
+		}
+		if o.Row+N > mg.Rows {
+			mg.Rows = o.Row + N
+		}
+		if o.Col+N > mg.Cols {
+			mg.Cols = o.Col + N
+		}
+	}
+
+	mg.Cells = make([][]int, mg.Rows)
+	mg.gridsOf = make([][][]int, mg.Rows)
+	for r := range mg.Cells {
+		mg.Cells[r] = make([]int, mg.Cols)
+		mg.gridsOf[r] = make([][]int, mg.Cols)
+		for c := range mg.Cells[r] {
+			mg.Cells[r][c] = -1
+		}
+	}
+	for g, o := range offsets {
+		for i := 0; i < N; i++ {
+			for j := 0; j < N; j++ {
+				mg.Cells[o.Row+i][o.Col+j] = 0
+				mg.gridsOf[o.Row+i][o.Col+j] = append(mg.gridsOf[o.Row+i][o.Col+j], g)
+			}
+		}
+	}
+	return mg, nil
+}
+
+// Parse a multi-grid puzzle. The grids are given by a known layout or by their offsets
+// (0 based), followed by the rows of the combined layout:
+//
+//	layout samurai       or   grid 0 0
+//	                          grid 6 6
+//	..3.....5   .8..1....
+//
+// Digits are clues, '.' or '0' empty cells. Positions outside the grids are ignored.
+// Blank lines before and after the rows and lines starting with # are skipped.
+func ParseMultiGrid(s string) (*MultiGrid, error) {
+	var (
+		offsets []Coord
+		rows    []string
+		lineNos []int
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		fields := strings.Fields(line)
+		if len(fields) == 0 && len(rows) == 0 || len(fields) > 0 && strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		switch {
+		case len(rows) == 0 && len(fields) > 0 && fields[0] == "layout":
+			if len(fields) != 2 || multiLayouts[strings.ToLower(fields[1])] == nil {
+				return nil, fmt.Errorf("line %d: unknown layout %q", lineNo, strings.Join(fields[1:], " "))
+			}
+			offsets = append(offsets, multiLayouts[strings.ToLower(fields[1])]...)
+		case len(rows) == 0 && len(fields) > 0 && fields[0] == "grid":
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("line %d: expected grid row col", lineNo)
+			}
+			r, err1 := strconv.Atoi(fields[1])
+			c, err2 := strconv.Atoi(fields[2])
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("line %d: invalid grid offset", lineNo)
+			}
+			offsets = append(offsets, Coord{Row: r, Col: c})
+		default:
+			rows = append(rows, line)
+			lineNos = append(lineNos, lineNo)
+		}
+	}
+
+	// blank lines inside the rows are rows without cells, those after them are not
+	for len(rows) > 0 && strings.TrimSpace(rows[len(rows)-1]) == "" {
+		rows, lineNos = rows[:len(rows)-1], lineNos[:len(lineNos)-1]
+	}
+
+	mg, err := NewMultiGrid(offsets)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) != mg.Rows {
+		return nil, fmt.Errorf("expected %d rows but got %d", mg.Rows, len(rows))
+	}
+
+	for r, row := range rows {
+		for c := 0; c < mg.Cols; c++ {
+			if mg.Cells[r][c] < 0 {
+				continue
+			}
+			if c >= len(row) {
+				return nil, fmt.Errorf("line %d: row is too short", lineNos[r])
+			}
+			switch ch := row[c]; {
+			case ch == '.' || ch == '0':
+			case ch >= '1' && ch <= '9':
+				mg.Cells[r][c] = int(ch - '0')
+			default:
+				return nil, fmt.Errorf("line %d: invalid character %q at col %d", lineNos[r], ch, c+1)
+			}
+		}
+	}
+	return mg, nil
+}
+
+// Read a multi-grid puzzle file
+func ReadMultiGrid(fname string) (*MultiGrid, error) {
+	b, err := os.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+
+	mg, err := ParseMultiGrid(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fname, err)
+	}
+	return mg, nil
+}
+
+// The N x N grid g of the multi-grid
+func (mg *MultiGrid) Grid(g int) Intmat {
+	var m Intmat
+
+	o := mg.Offsets[g]
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			m[i][j] = mg.Cells[o.Row+i][o.Col+j]
+		}
+	}
+	return m
+}
+
+// Copy the digits of m into grid g and so into the grids sharing its cells.
+// Returns the number of cells filled.
+func (mg *MultiGrid) SetGrid(g int, m Intmat) (int, error) {
+	cnt := 0
+
+	o := mg.Offsets[g]
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			cur := &mg.Cells[o.Row+i][o.Col+j]
+			switch {
+			case m[i][j] == 0 || m[i][j] == *cur:
+			case *cur != 0:
+				return cnt, fmt.Errorf("grid %d: [%d,%d] is %d but got %d", g+1, i, j, *cur, m[i][j])
+			default:
+				*cur = m[i][j]
+				cnt++
+			}
+		}
+	}
+	return cnt, nil
+}
+
+// Count the empty cells of all grids
+func (mg *MultiGrid) CountEmpty() int {
+	cnt := 0
+
+	for _, row := range mg.Cells {
+		for _, v := range row {
+			if v == 0 {
+				cnt++
+			}
+		}
+	}
+	return cnt
+}
+
+// Returns true if num may be placed at [row,col] of the combined layout, checking
+// the row, col and block of every grid containing the cell
+func (mg *MultiGrid) IsSafe(row, col, num int) bool {
+	for _, g := range mg.gridsOf[row][col] {
+		o := mg.Offsets[g]
+		i, j := row-o.Row, col-o.Col
+
+		for k := 0; k < N; k++ {
+			if mg.Cells[o.Row+i][o.Col+k] == num || mg.Cells[o.Row+k][o.Col+j] == num {
+				return false
+			}
+		}
+		bi, bj := i-i%SQ, j-j%SQ
+		for x := bi; x < bi+SQ; x++ {
+			for y := bj; y < bj+SQ; y++ {
+				if mg.Cells[o.Row+x][o.Col+y] == num {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// Returns true if every grid is completely filled and valid
+func (mg *MultiGrid) Solved() bool {
+	for g := range mg.Offsets {
+		m := mg.Grid(g)
+		if CountEmpty(m) > 0 {
+			return false
+		}
+		for i := 0; i < N; i++ {
+			for j := 0; j < N; j++ {
+				num := m[i][j]
+				m[i][j] = 0
+				if !isSafeStd(m, i, j, num) {
+					return false
+				}
+				m[i][j] = num
+			}
+		}
+	}
+	return true
+}
+
+// IsSafe for a classic grid, ignoring the jigsaw regions and variants
+func isSafeStd(m Intmat, row, col, num int) bool {
+	for k := 0; k < N; k++ {
+		if m[row][k] == num || m[k][col] == num {
+			return false
+		}
+	}
+	bi, bj := row-row%SQ, col-col%SQ
+	for x := bi; x < bi+SQ; x++ {
+		for y := bj; y < bj+SQ; y++ {
+			if m[x][y] == num {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Solve the remaining cells by backtracking, always filling the cell with the fewest
+// candidates first. Returns the number of cells tried.
+func (mg *MultiGrid) Solve() (bool, int) {
+	iter := 0
+	ms := &multiSearch{mg: mg}
+	if backtrack(ms, 1, nil, &iter) == 0 {
+		return false, iter
+	}
+	for r := range mg.Cells {
+		copy(mg.Cells[r], ms.solution[r])
+	}
+	return true, iter
+}
+
+// Count the solutions up to limit
+func (mg *MultiGrid) CountSolutions(limit int) int {
+	return backtrack(&multiSearch{mg: mg}, limit, nil, nil)
+}
+
+// MultiGrid searched by backtrack, keeping the first solution
+type multiSearch struct {
+	mg       *MultiGrid
+	solution [][]int
+}
+
+func (ms *multiSearch) nextCell() (int, int, []int) { return ms.mg.bestCell() }
+func (ms *multiSearch) set(row, col, d int)         { ms.mg.Cells[row][col] = d }
+func (ms *multiSearch) unset(row, col, d int)       { ms.mg.Cells[row][col] = 0 }
+
+func (ms *multiSearch) found() {
+	if ms.solution != nil {
+		return
+	}
+	for _, row := range ms.mg.Cells {
+		ms.solution = append(ms.solution, append([]int(nil), row...))
+	}
+}
+
+// The empty cell with the fewest candidates and its candidates. Row is -1 if there is no empty cell.
+func (mg *MultiGrid) bestCell() (int, int, []int) {
+	row, col := -1, -1
+	var best []int
+
+	for r := range mg.Cells {
+		for c, v := range mg.Cells[r] {
+			if v != 0 {
+				continue
+			}
+			var cands []int
+			for d := 1; d <= N; d++ {
+				if mg.IsSafe(r, c, d) {
+					cands = append(cands, d)
+				}
+			}
+			if row < 0 || len(cands) < len(best) {
+				row, col, best = r, c, cands
+				if len(cands) == 0 {
+					return row, col, nil
+				}
+			}
+		}
+	}
+	return row, col, best
+}
+
+// Print the combined layout. Blocks alternate colours as in PrintSudoku. The line and
+// box styles print the layout as read, with . for empty cells.
+func (mg *MultiGrid) Print() {
+	if GridStyle != GridRows && GridStyle != GridLegacy {
+		fmt.Print(mg.String())
+		return
+	}
+	for r, row := range mg.Cells {
+		line := ""
+		for c, v := range row {
+			switch {
+			case v < 0:
+				line += "  "
+			case (r/SQ+c/SQ)%2 == 1:
+				line += color.LightBlue.Sprintf("%s ", CellText(v))
+			default:
+				line += color.LightGreen.Sprintf("%s ", CellText(v))
+			}
+		}
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+	fmt.Println(strings.Repeat("-", 2*mg.Cols-1))
+}
+
+// The combined layout in the input format, without the offsets
+func (mg *MultiGrid) String() string {
+	var sb strings.Builder
+
+	for _, row := range mg.Cells {
+		line := ""
+		for _, v := range row {
+			switch {
+			case v < 0:
+				line += " "
+			case v == 0:
+				line += "."
+			default:
+				line += strconv.Itoa(v)
+			}
+		}
+		sb.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,212 @@
+package lib
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/color"
+	colorv1 "gopkg.in/gookit/color.v1"
+)
+
+// Styles of the grids printed by PrintSudoku
+const (
+	GridRows   = "rows"   // a row per line with the digits separated by spaces, . for empty cells
+	GridBox    = "box"    // box-drawing lines between the blocks, . for empty cells
+	GridLine   = "line"   // 81 characters on a line, . for empty cells
+	GridLegacy = "legacy" // rows with 0 for empty cells and the wide table of candidates, as before the styles
+)
+
+// Style of the grids printed by PrintSudoku
+var GridStyle = GridRows
+
+var gridStyles = []string{GridRows, GridBox, GridLine, GridLegacy}
+
+// Set the style of the printed grids. Returns an error for an unknown style.
+func SetGridStyle(style string) error {
+	for _, s := range gridStyles {
+		if s == style {
+			GridStyle = style
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown grid style %q, expected one of %s", style, strings.Join(gridStyles, ", "))
+}
+
+// Text of a digit of a grid: . for an empty cell, or 0 in the legacy style
+func CellText(d int) string {
+	if d == 0 && GridStyle != GridLegacy {
+		return "."
+	}
+	return strconv.Itoa(d)
+}
+
+// Switch the colours of all printing on or off. The packages print with both versions
+// of gookit/color, so both are switched.
+func SetColor(on bool) {
+	color.Enable = on
+	colorv1.Enable = on
+}
+
+// True if the file is a terminal rather than a file or a pipe
+func IsTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// Print the grid with box-drawing lines between the blocks. Blocks alternate colours
+// as in PrintSudoku, so jigsaw regions still show.
+func printBoxGrid(m Intmat) {
+	border := func(left, mid, right string) string {
+		seg := strings.Repeat("─", 2*SQ+1)
+		return left + strings.Repeat(seg+mid, SQ-1) + seg + right
+	}
+
+	fmt.Println(border("┌", "┬", "┐"))
+	for i := 0; i < N; i++ {
+		if i > 0 && i%SQ == 0 {
+			fmt.Println(border("├", "┼", "┤"))
+		}
+		for j := 0; j < N; j++ {
+			if j%SQ == 0 {
+				fmt.Print("│ ")
+			}
+			cell := CellText(m[i][j])
+			if BlkOf(i, j)%2 == 1 {
+				color.LightBlue.Print(cell)
+			} else {
+				color.LightGreen.Print(cell)
+			}
+			fmt.Print(" ")
+		}
+		fmt.Println("│")
+	}
+	fmt.Println(border("└", "┴", "┘"))
+}
+
+// Print the candidates in the grid style other than legacy. Cells without candidates
+// are . and the candidates of a cell are written together, e.g. 137. The line style
+// prints a field per cell on one line, as read by the pencil marks files.
+func printCandidates(pm Pmat) {
+	text := func(i, j int) string {
+		if len(pm[i][j]) == 0 {
+			return "."
+		}
+		return arr2String(pm[i][j], "")
+	}
+
+	if GridStyle == GridLine {
+		var fields []string
+		for i := 0; i < N; i++ {
+			for j := 0; j < N; j++ {
+				fields = append(fields, text(i, j))
+			}
+		}
+		fmt.Println(strings.Join(fields, " "))
+		return
+	}
+
+	// columns as wide as their widest cell
+	var width [N]int
+	for j := 0; j < N; j++ {
+		for i := 0; i < N; i++ {
+			width[j] = maxInt(width[j], len(text(i, j)))
+		}
+	}
+	vert, horiz, cross := "|", "-", "+"
+	if GridStyle == GridBox {
+		vert, horiz, cross = "│", "─", "┼"
+	}
+	border := func(left, mid, right string) string {
+		line := left
+		for b := 0; b < SQ; b++ {
+			seg := 1
+			for j := b * SQ; j < (b+1)*SQ; j++ {
+				seg += width[j] + 1
+			}
+			if b > 0 {
+				line += mid
+			}
+			line += strings.Repeat(horiz, seg)
+		}
+		return line + right
+	}
+
+	if GridStyle == GridBox {
+		fmt.Println(border("┌", "┬", "┐"))
+	}
+	for i := 0; i < N; i++ {
+		if i > 0 && i%SQ == 0 {
+			if GridStyle == GridBox {
+				fmt.Println(border("├", cross, "┤"))
+			} else {
+				fmt.Println(border("", cross, ""))
+			}
+		}
+		line := ""
+		for j := 0; j < N; j++ {
+			switch {
+			case j%SQ != 0:
+			case GridStyle == GridBox || j > 0:
+				line += vert + " "
+			default:
+				line += " "
+			}
+			line += fmt.Sprintf("%-*s ", width[j], text(i, j))
+		}
+		if GridStyle == GridBox {
+			line += vert
+		}
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+	if GridStyle == GridBox {
+		fmt.Println(border("└", "┴", "┘"))
+	}
+}
+
+// Cell with its digits, for printing lists of cells
+type CellDigits struct {
+	Row, Col int
+	Vals     []int
+}
+
+// Print groups of cells in the line or box style: in line, all on one line in the
+// standard notation, e.g. r3c5{17}, with the groups separated by ;. In box, the digits
+// of the cells on a grid of candidates. Returns false for the other styles, in which
+// the lists print a line per group themselves.
+func PrintCellGroups(desc string, groups [][]CellDigits) bool {
+	if GridStyle != GridLine && GridStyle != GridBox {
+		return false
+	}
+	if len(groups) == 0 {
+		return true
+	}
+
+	if GridStyle == GridLine {
+		var list []string
+		for _, g := range groups {
+			var cells []string
+			for _, c := range g {
+				cells = append(cells, fmt.Sprintf("%s{%s}", CellName(c.Row, c.Col), arr2String(c.Vals, "")))
+			}
+			list = append(list, strings.Join(cells, " "))
+		}
+		fmt.Printf("%s: %s\n", desc, strings.Join(list, "; "))
+		return true
+	}
+
+	var pm Pmat
+	for _, g := range groups {
+		for _, c := range g {
+			for _, d := range c.Vals {
+				if !Contains(pm[c.Row][c.Col], d) {
+					pm[c.Row][c.Col] = append(pm[c.Row][c.Col], d)
+				}
+			}
+		}
+	}
+	fmt.Printf("%s:\n", desc)
+	printCandidates(pm)
+	return true
+}
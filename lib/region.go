@@ -0,0 +1,128 @@
+package lib
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Region holds the block (region) index of every cell. A standard sudoku uses
+// SQ x SQ squares; a jigsaw sudoku may use any N regions of N cells each.
+var Region [N][N]int
+
+// cells belonging to each region, in row-major order
+var regionCells [N][]Coord
+
+func init() {
+	SetStdRegions()
+}
+
+// Reset the region map to the standard SQ x SQ blocks
+func SetStdRegions() {
+	var r [N][N]int
+
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			r[i][j] = i/SQ*SQ + j/SQ
+		}
+	}
+	SetRegions(r)
+}
+
+// Install a custom region map. Every region index must be in 0..N-1 and
+// every region must have exactly N cells.
+func SetRegions(r [N][N]int) error {
+	var cells [N][]Coord
+
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			if r[i][j] < 0 || r[i][j] >= N {
+				return fmt.Errorf("cell [%d,%d] has invalid region %d", i, j, r[i][j])
+			}
+			cells[r[i][j]] = append(cells[r[i][j]], Coord{Row: i, Col: j})
+		}
+	}
+
+	for b := 0; b < N; b++ {
+		if len(cells[b]) != N {
+			return fmt.Errorf("region %d has %d cells, expected %d", b, len(cells[b]), N)
+		}
+	}
+
+	Region = r
+	regionCells = cells
+	return nil
+}
+
+// Returns true if the region map is the standard SQ x SQ layout
+func IsStdRegions() bool {
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			if Region[i][j] != i/SQ*SQ+j/SQ {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Region index of cell [row,col]
+func BlkOf(row, col int) int {
+	return Region[row][col]
+}
+
+// Cells of the specified region
+func BlkCells(blk int) []Coord {
+	return regionCells[blk]
+}
+
+// Parse a region map. The string holds N*N symbols, one per cell in row-major order.
+// Any symbol may be used to name a region (e.g. 1-9 or a-i). Whitespace is ignored
+// so the map may be laid out as N lines of N symbols.
+func ParseRegions(s string) ([N][N]int, error) {
+	var (
+		r   [N][N]int
+		pos int
+	)
+	ids := map[rune]int{}
+
+	for _, ch := range s {
+		if unicode.IsSpace(ch) {
+			continue
+		}
+		if pos >= nsize {
+			return r, fmt.Errorf("region map has more than %d cells", nsize)
+		}
+
+		id, ok := ids[ch]
+		if !ok {
+			id = len(ids)
+			if id >= N {
+				return r, fmt.Errorf("region map has more than %d regions", N)
+			}
+			ids[ch] = id
+		}
+		r[pos/N][pos%N] = id
+		pos++
+	}
+
+	if pos != nsize {
+		return r, fmt.Errorf("region map has %d cells, expected %d", pos, nsize)
+	}
+	return r, nil
+}
+
+// Read a region map file and install it
+func ReadRegions(fname string) error {
+	b, err := os.ReadFile(fname)
+	if err != nil {
+		return err
+	}
+
+	r, err := ParseRegions(strings.TrimSpace(string(b)))
+	if err != nil {
+		return fmt.Errorf("%s: %v", fname, err)
+	}
+	return SetRegions(r)
+}
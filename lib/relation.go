@@ -0,0 +1,300 @@
+package lib
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+// Kinds of relation between 2 orthogonally adjacent cells
+const (
+	KropkiWhite = "w" // consecutive digits
+	KropkiBlack = "b" // one digit is double the other
+	SumX        = "x" // digits add up to 10
+	SumV        = "v" // digits add up to 5
+	Greater     = ">" // first cell is greater than the second
+)
+
+// Relation between the digits of 2 adjacent cells, e.g. a Kropki dot
+type Relation struct {
+	Kind string
+	A, B Coord
+}
+
+// Parity marked cell which must hold an even (or odd) digit
+type Parity struct {
+	Cell Coord
+	Even bool
+}
+
+// Relations and parity cells of the current puzzle, kept for PrintSudoku
+var (
+	Relations []Relation
+	Parities  []Parity
+)
+
+func (rel Relation) holds(a, b int) bool {
+	switch rel.Kind {
+	case KropkiWhite:
+		return a-b == 1 || b-a == 1
+	case KropkiBlack:
+		return a == 2*b || b == 2*a
+	case SumX:
+		return a+b == 10
+	case SumV:
+		return a+b == 5
+	case Greater:
+		return a > b
+	}
+	return true
+}
+
+func (rel Relation) Valid(m Intmat, row, col, num int) bool {
+	if rel.A.Row == row && rel.A.Col == col {
+		if v := m[rel.B.Row][rel.B.Col]; v != 0 {
+			return rel.holds(num, v)
+		}
+	} else if rel.B.Row == row && rel.B.Col == col {
+		if v := m[rel.A.Row][rel.A.Col]; v != 0 {
+			return rel.holds(v, num)
+		}
+	}
+	return true
+}
+
+// Prune erases the candidates of either cell without a partner in the other cell
+func (rel Relation) Prune(m Intmat, m2 Pmat) []Elim {
+	var elims []Elim
+
+	valsA := cellValues(m, m2, rel.A)
+	valsB := cellValues(m, m2, rel.B)
+
+	if m[rel.A.Row][rel.A.Col] == 0 {
+		for _, a := range m2[rel.A.Row][rel.A.Col] {
+			if !rel.anyHolds(a, valsB, true) {
+				elims = append(elims, Elim{Row: rel.A.Row, Col: rel.A.Col, Dig: a})
+			}
+		}
+	}
+	if m[rel.B.Row][rel.B.Col] == 0 {
+		for _, b := range m2[rel.B.Row][rel.B.Col] {
+			if !rel.anyHolds(b, valsA, false) {
+				elims = append(elims, Elim{Row: rel.B.Row, Col: rel.B.Col, Dig: b})
+			}
+		}
+	}
+	return elims
+}
+
+// Returns true if v and any of the other cell's values satisfy the relation
+func (rel Relation) anyHolds(v int, others []int, first bool) bool {
+	for _, o := range others {
+		if first && rel.holds(v, o) || !first && rel.holds(o, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// The placed digit or the candidates of a cell
+func cellValues(m Intmat, m2 Pmat, c Coord) []int {
+	if m[c.Row][c.Col] != 0 {
+		return []int{m[c.Row][c.Col]}
+	}
+	return m2[c.Row][c.Col]
+}
+
+func (p Parity) Valid(m Intmat, row, col, num int) bool {
+	if p.Cell.Row == row && p.Cell.Col == col {
+		return (num%2 == 0) == p.Even
+	}
+	return true
+}
+
+func (p Parity) Prune(m Intmat, m2 Pmat) []Elim {
+	var elims []Elim
+
+	for _, d := range m2[p.Cell.Row][p.Cell.Col] {
+		if (d%2 == 0) != p.Even {
+			elims = append(elims, Elim{Row: p.Cell.Row, Col: p.Cell.Col, Dig: d})
+		}
+	}
+	return elims
+}
+
+// Install the relations and parity cells as constraints
+func SetMarks(rels []Relation, pars []Parity) error {
+	for _, rel := range rels {
+		dr, dc := rel.A.Row-rel.B.Row, rel.A.Col-rel.B.Col
+		if dr*dr+dc*dc != 1 {
+			return fmt.Errorf("cells [%d,%d] and [%d,%d] are not adjacent", rel.A.Row, rel.A.Col, rel.B.Row, rel.B.Col)
+		}
+	}
+
+	Relations = append(Relations, rels...)
+	Parities = append(Parities, pars...)
+	for _, rel := range rels {
+		AddConstraint(rel)
+	}
+	for _, p := range pars {
+		AddConstraint(p)
+	}
+	return nil
+}
+
+// Parse the marks of a Kropki, XV, inequality or even/odd puzzle. Each line holds a kind
+// followed by cells in row/col notation starting from 1:
+//
+//	w r1c1 r1c2   white dot, consecutive digits
+//	b r1c1 r2c1   black dot, one digit is double the other
+//	x r1c1 r1c2   digits add up to 10
+//	v r1c1 r1c2   digits add up to 5
+//	> r1c1 r1c2   first cell is greater (< for less)
+//	e r1c1 r5c5   even cells
+//	o r2c2        odd cells
+//
+// Blank lines and lines starting with # are skipped.
+func ParseMarks(s string) ([]Relation, []Parity, error) {
+	var (
+		rels []Relation
+		pars []Parity
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		kind := strings.ToLower(fields[0])
+		var cells []Coord
+		for _, f := range fields[1:] {
+			c, err := ParseCell(f)
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: %v", lineNo, err)
+			}
+			cells = append(cells, c)
+		}
+
+		switch kind {
+		case "e", "o":
+			if len(cells) == 0 {
+				return nil, nil, fmt.Errorf("line %d: no cells", lineNo)
+			}
+			for _, c := range cells {
+				pars = append(pars, Parity{Cell: c, Even: kind == "e"})
+			}
+		case KropkiWhite, KropkiBlack, SumX, SumV, Greater, "<":
+			if len(cells) != 2 {
+				return nil, nil, fmt.Errorf("line %d: expected 2 cells but got %d", lineNo, len(cells))
+			}
+			if kind == "<" {
+				rels = append(rels, Relation{Kind: Greater, A: cells[1], B: cells[0]})
+			} else {
+				rels = append(rels, Relation{Kind: kind, A: cells[0], B: cells[1]})
+			}
+		default:
+			return nil, nil, fmt.Errorf("line %d: unknown mark %q", lineNo, fields[0])
+		}
+	}
+	return rels, pars, nil
+}
+
+// Read a marks file and install the relations and parity cells
+func ReadMarks(fname string) error {
+	b, err := os.ReadFile(fname)
+	if err != nil {
+		return err
+	}
+
+	rels, pars, err := ParseMarks(string(b))
+	if err != nil {
+		return fmt.Errorf("%s: %v", fname, err)
+	}
+	return SetMarks(rels, pars)
+}
+
+// Symbol drawn between cells a and b by PrintSudoku
+func relationSymbol(a, b Coord) string {
+	for _, rel := range Relations {
+		fwd := rel.A == a && rel.B == b
+		if !fwd && !(rel.A == b && rel.B == a) {
+			continue
+		}
+
+		switch rel.Kind {
+		case KropkiWhite:
+			return "○"
+		case KropkiBlack:
+			return "●"
+		case SumX:
+			return "X"
+		case SumV:
+			return "V"
+		case Greater:
+			// the symbol opens towards the greater cell
+			horiz := a.Row == b.Row
+			switch {
+			case horiz && fwd:
+				return ">"
+			case horiz:
+				return "<"
+			case fwd:
+				return "v"
+			default:
+				return "^"
+			}
+		}
+	}
+	return " "
+}
+
+// Parity of a marked cell: "E" for even, "O" for odd, "" if not marked
+func paritySymbol(row, col int) string {
+	for _, p := range Parities {
+		if p.Cell.Row == row && p.Cell.Col == col {
+			if p.Even {
+				return "E"
+			}
+			return "O"
+		}
+	}
+	return ""
+}
+
+// Print the sudoku with the relation symbols between the cells. Empty parity cells
+// show E or O.
+func printSudokuMarks(m Intmat) {
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			cell := CellText(m[i][j])
+			if p := paritySymbol(i, j); p != "" && m[i][j] == 0 {
+				cell = p
+			}
+			if BlkOf(i, j)%2 == 1 {
+				color.LightBlue.Print(cell)
+			} else {
+				color.LightGreen.Print(cell)
+			}
+			if j < N-1 {
+				fmt.Print(relationSymbol(Coord{Row: i, Col: j}, Coord{Row: i, Col: j + 1}))
+			}
+		}
+		fmt.Println()
+
+		if i < N-1 {
+			line := ""
+			for j := 0; j < N; j++ {
+				line += relationSymbol(Coord{Row: i, Col: j}, Coord{Row: i + 1, Col: j}) + " "
+			}
+			fmt.Println(strings.TrimRight(line, " "))
+		}
+	}
+	fmt.Println("-----------------")
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+var lines1Sol = "826947315153628497794531286679284531438195762215763849582416973367859124941372658"
+
+// constraint which allows nothing but 5 in a cell
+type onlyFive struct{ Coord }
+
+func (c onlyFive) Valid(m Intmat, row, col, num int) bool {
+	return row != c.Row || col != c.Col || num == 5
+}
+
+func (c onlyFive) Prune(m Intmat, m2 Pmat) []Elim {
+	var elims []Elim
+	for _, d := range m2[c.Row][c.Col] {
+		if d != 5 {
+			elims = append(elims, Elim{Row: c.Row, Col: c.Col, Dig: d})
+		}
+	}
+	return elims
+}
+
+func init() {
+	RegisterConstraint("five", func(args []string) (Constraint, error) {
+		c, err := ParseCell(args[0])
+		return onlyFive{c}, err
+	})
+}
+
+func TestParseConstraints(t *testing.T) {
+	list, err := ParseConstraints("# comment\n\nthermo r1c1 r1c2 r2c3\nSandwich col 4 12\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("Expected 2 constraints but got %v.\n", list)
+	}
+
+	bad := []string{
+		"thermo r1c1 r1c3\n",
+		"arrow r1c1\n",
+		"littlekiller 10 r1c1 r2c2 r3c4\n",
+		"sandwich row 10 3\n",
+		"spiral r1c1\n",
+	}
+	for _, s := range bad {
+		if _, err := ParseConstraints("\n" + s); err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Fatalf("Expected error at line 2 for %q but got %v.\n", s, err)
+		}
+	}
+
+	list, err = ParseConstraints("five r3c3\n")
+	if err != nil || len(list) != 1 {
+		t.Fatalf("Expected registered constraint but got %v, %v.\n", list, err)
+	}
+}
+
+func TestLinesPrune(t *testing.T) {
+	s := newSolver()
+
+	defer ClearConstraints()
+
+	list, _ := ParseConstraints("thermo r1c1 r1c2 r1c3\narrow r9c9 r9c8 r8c8\nlittlekiller 3 r1c8 r2c9\nsandwich row 5 0\nfive r3c3\n")
+	for _, c := range list {
+		AddConstraint(c)
+	}
+
+	s.PrepPmat(strings.Repeat(".", 81))
+
+	tests := []struct {
+		row, col int
+		want     []int
+	}{
+		{0, 0, []int{1, 2, 3, 4, 5, 6, 7}},
+		{0, 2, []int{3, 4, 5, 6, 7, 8, 9}},
+		{8, 8, []int{2, 3, 4, 5, 6, 7, 8, 9}},
+		{8, 7, []int{1, 2, 3, 4, 5, 6, 7, 8}},
+		{1, 8, []int{1, 2}},
+		{2, 2, []int{5}},
+	}
+
+	for _, tc := range tests {
+		if !IntArrayEquals(s.mat2[tc.row][tc.col], tc.want) {
+			t.Fatalf("Expected %v at [%d,%d] but got %v.\n", tc.want, tc.row, tc.col, s.mat2[tc.row][tc.col])
+		}
+	}
+}
+
+func TestSandwichPrune(t *testing.T) {
+	s := newSolver()
+
+	defer ClearConstraints()
+
+	list, err := ParseConstraints("sandwich row 1 35\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	AddConstraint(list[0])
+
+	// 35 is 2+...+8 so the crusts are at either end of the row
+	s.PrepPmat(strings.Repeat(".", 81))
+
+	for j := 1; j < N-1; j++ {
+		if Contains(s.mat2[0][j], 1) || Contains(s.mat2[0][j], N) {
+			t.Fatalf("Expected no crust at [0,%d] but got %v.\n", j, s.mat2[0][j])
+		}
+	}
+	if !IntArrayEquals(s.mat2[0][0], []int{1, 9}) {
+		t.Fatalf("Expected [1 9] but got %v.\n", s.mat2[0][0])
+	}
+}
+
+func TestLines(t *testing.T) {
+	s := newSolver()
+
+	defer ClearConstraints()
+
+	if err := ReadConstraints("lines1_lines.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile("lines1.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.PrepPmat(strings.TrimSpace(string(b)))
+
+	s.RuleLoop(s.rule3, RuleTable[3], Zero)
+	s.RuleLoop(s.rule1, RuleTable[1], Zero)
+
+	s.mat3 = s.mat
+	s.iterMat(s.emptyL.Head)
+
+	if MatToString(s.mat3) != lines1Sol {
+		t.Fatalf("Expected %s but got %s.\n", lines1Sol, MatToString(s.mat3))
+	}
+
+	if !ConstraintsHold(s.mat3) {
+		t.Fatal("Expected line constraints to hold.")
+	}
+}
@@ -128,8 +128,28 @@ func (p *LinkedList) EraseDigitFromCell(row, col, dig int) {
 	node.Vals = EraseFromSlice(node.Vals, dig)
 }
 
+// insert node into linked list in row major order, e.g. to put back a deleted node
 func (p *LinkedList) InsNode(node *Cell) {
+	var prev *Cell
 
+	next := p.Head
+	for next != nil && (next.Row < node.Row || next.Row == node.Row && next.Col < node.Col) {
+		prev = next
+		next = next.Next
+	}
+
+	node.Prev = prev
+	node.Next = next
+	if prev == nil {
+		p.Head = node
+	} else {
+		prev.Next = node
+	}
+	if next == nil {
+		p.Last = node
+	} else {
+		next.Prev = node
+	}
 }
 
 // remove current node from linked list and connect prev and next nodes
@@ -155,6 +175,14 @@ func (p *LinkedList) DelNode(node *Cell) {
 }
 
 func (p *LinkedList) PrintResult(desc string) {
+	var groups [][]CellDigits
+	for c := p.Head; c != nil; c = c.Next {
+		groups = append(groups, []CellDigits{{Row: c.Row, Col: c.Col, Vals: c.Vals}})
+	}
+	if PrintCellGroups(desc, groups) {
+		return
+	}
+
 	currNode := p.Head
 	for currNode != nil {
 		color.LightMagenta.Printf("%s %d at [%d][%d].\n", desc, currNode.Vals, currNode.Row, currNode.Col)
@@ -167,10 +195,10 @@ func GetPossibleMat(mat Intmat) (*LinkedList, Pmat) {
 		ncols = 9
 	)
 	var (
-		mat2                Pmat
-		emptyL              *LinkedList
-		inRow, inCol, inSqu bool
-		valList             []int
+		mat2                         Pmat
+		emptyL                       *LinkedList
+		inRow, inCol, inSqu, inHouse bool
+		valList                      []int
 	)
 
 	emptyL = CreatelinkedList()
@@ -191,15 +219,42 @@ func GetPossibleMat(mat Intmat) (*LinkedList, Pmat) {
 					inRow = Contains(GetArrForRow(mat, i), p)
 					inCol = Contains(GetArrForCol(mat, j), p)
 					inSqu = Contains(GetArrForSqu(mat, i, j), p)
+					inHouse = InHouses(mat, i, j, p)
 
-					if !inRow && !inCol && !inSqu {
+					if !inRow && !inCol && !inSqu && !inHouse {
 						mat2[i][j] = append(mat2[i][j], p)
-						valList = append(valList, p)
 					}
 				}
+			}
+		}
+	}
+
+	// variants: remove the candidates ruled out by constraints, e.g. killer cages
+	for elims := PruneConstraints(mat, mat2); len(elims) > 0; elims = PruneConstraints(mat, mat2) {
+		ApplyElims(&mat2, elims)
+	}
+
+	for i := 0; i < ncols; i++ {
+		for j := 0; j < ncols; j++ {
+			if mat[i][j] == 0 {
+				valList = append([]int(nil), mat2[i][j]...)
 				emptyL.AddCell(i, j, valList)
 			}
 		}
 	}
 	return emptyL, mat2
 }
+
+// Build the list of empty cells of mat with the candidates of mat2, e.g. pencil marks
+func ListFromPossibleMat(mat Intmat, mat2 Pmat) *LinkedList {
+	emptyL := CreatelinkedList()
+
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			if mat[i][j] == 0 {
+				emptyL.AddCell(i, j, append([]int(nil), mat2[i][j]...))
+			}
+		}
+	}
+	return emptyL
+}
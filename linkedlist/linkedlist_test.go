@@ -1,6 +1,10 @@
 package linkedlist
 
-import "testing"
+import (
+	"testing"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
 
 func TestAddNode(t *testing.T) {
 	cell1 := &Cell{
@@ -51,3 +55,60 @@ func TestAddCell(t *testing.T) {
 		t.Fatalf("Expected 1 count but got %d.\n", el.CountNodes())
 	}
 }
+
+func TestListFromPossibleMat(t *testing.T) {
+	var (
+		mat  Intmat
+		mat2 Pmat
+	)
+	mat[0][0] = 5
+	mat2[0][1] = []int{1, 2}
+	mat2[8][8] = []int{3}
+
+	el := ListFromPossibleMat(mat, mat2)
+	if el.CountNodes() != N*N-1 {
+		t.Fatalf("Expected %d count but got %d.\n", N*N-1, el.CountNodes())
+	}
+	if el.Head.Row != 0 || el.Head.Col != 1 || el.CountElem() != 3 {
+		t.Fatalf("Expected head [0,1] and 3 candidates but got [%d,%d] and %d.\n", el.Head.Row, el.Head.Col, el.CountElem())
+	}
+
+	// the list has its own copy of the candidates
+	el.EraseDigitFromCell(0, 1, 1)
+	if len(mat2[0][1]) != 2 || mat2[0][1][0] != 1 {
+		t.Fatalf("Expected [1 2] but got %v.\n", mat2[0][1])
+	}
+}
+
+func TestInsNode(t *testing.T) {
+	el := CreatelinkedList()
+	el.AddCell(1, 4, []int{3, 4})
+	el.AddCell(6, 7, []int{2, 8})
+
+	el.InsNode(&Cell{Row: 4, Col: 5, Vals: []int{2, 8}})
+	el.InsNode(&Cell{Row: 0, Col: 2, Vals: []int{1}})
+	el.InsNode(&Cell{Row: 8, Col: 0, Vals: []int{9}})
+
+	expected := []Coord{{Row: 0, Col: 2}, {Row: 1, Col: 4}, {Row: 4, Col: 5}, {Row: 6, Col: 7}, {Row: 8, Col: 0}}
+	k := 0
+	for currN := el.Head; currN != nil; currN = currN.Next {
+		if currN.Row != expected[k].Row || currN.Col != expected[k].Col {
+			t.Fatalf("Expected [%d,%d] at %d but got [%d,%d].\n", expected[k].Row, expected[k].Col, k, currN.Row, currN.Col)
+		}
+		if k > 0 && currN.Prev.Row != expected[k-1].Row {
+			t.Fatalf("Expected prev row %d at %d but got %d.\n", expected[k-1].Row, k, currN.Prev.Row)
+		}
+		k++
+	}
+	if k != len(expected) || el.Last.Row != 8 {
+		t.Fatalf("Expected %d nodes ending at row 8 but got %d.\n", len(expected), k)
+	}
+
+	// put back a deleted node
+	node := el.GetNodeFoRCell(4, 5)
+	el.DelNode(node)
+	el.InsNode(node)
+	if el.CountNodes() != len(expected) || el.GetNodeFoRCell(1, 4).Next != node {
+		t.Fatal("Expected the deleted node back in its place.")
+	}
+}
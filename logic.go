@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	libcolor "github.com/gookit/color"
+	. "github.com/mjwong/sudoku2/lib"
+	. "github.com/mjwong/sudoku2/matchlist"
+)
+
+// Technique of the logical solver
+type technique struct {
+	rule   int
+	fn     func(*solver) (*Matchlist, int, time.Duration)
+	weight float64 // difficulty on the Sudoku Explainer scale
+	places bool    // places digits rather than erasing candidates
+}
+
+// Techniques in order of difficulty, simplest first
+var techniques = []technique{
+	{3, (*solver).rule3, 1.5, true},
+	{1, (*solver).rule1, 2.3, true},
+	{5, (*solver).rule5, 3.0, false},
+	{20, (*solver).rule20, 3.2, false},
+}
+
+// Profile of a logical solve: the techniques used and how often
+type Profile struct {
+	Steps   []int       // rule of each deduction in order
+	Counts  map[int]int // deductions per rule
+	Hardest int         // rule of the hardest technique used, 0 if none
+	Solved  bool        // false if the rules got stuck and guessing is needed
+	Events  []Event     // explanation of the steps
+}
+
+// Difficulty bands by the hardest technique needed, easiest first
+const (
+	BandSingles = "singles" // open and hidden singles
+	BandPairs   = "pairs"   // naked pairs
+	BandXwing   = "xwing"   // X-wing
+	BandGuess   = "guess"   // the rules get stuck, backtracking is needed
+)
+
+var bands = []string{BandSingles, BandPairs, BandXwing, BandGuess}
+
+// Band of the profile
+func (p Profile) Band() string {
+	switch {
+	case !p.Solved:
+		return BandGuess
+	case p.Hardest == 20:
+		return BandXwing
+	case p.Hardest == 5:
+		return BandPairs
+	default:
+		return BandSingles
+	}
+}
+
+// Returns an error for an unknown band name
+func checkBand(band string) error {
+	for _, b := range bands {
+		if b == band {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown band %q, expected one of %s", band, strings.Join(bands, ", "))
+}
+
+// Solve the puzzle with the rules only, one pass at a time. Each pass applies the
+// simplest technique which places a digit or erases a candidate, and each deduction of
+// the pass is a step of the profile. The output of the
+// rules is suppressed. Leaves the result in mat, mat2 and emptyL, and the explanation
+// of the steps in the profile.
+func (s *solver) solveLogic(input string) Profile {
+	restore := quiet()
+	defer restore()
+	defer func(saved bool) { s.explaining = saved }(s.explaining)
+	s.explaining, s.events = true, nil
+
+	s.PrepPmat(input)
+	p := Profile{Counts: map[int]int{}}
+	hardest := -1
+
+	for s.emptyL.CountNodes() > 0 {
+		progress := false
+		for k, t := range techniques {
+			nodes, elems := s.emptyL.CountNodes(), s.emptyL.CountElem()
+			before := len(s.events)
+			t.fn(s)
+			if s.emptyL.CountNodes() == nodes && s.emptyL.CountElem() == elems {
+				continue
+			}
+
+			// a pass of the rule can make many deductions, each is a step
+			steps := 0
+			for _, e := range s.events[before:] {
+				if len(e.Placements) > 0 || len(e.Elims) > 0 {
+					steps++
+				}
+			}
+			if steps == 0 {
+				steps = 1 // a change the explanation missed
+			}
+			for n := 0; n < steps; n++ {
+				p.Steps = append(p.Steps, t.rule)
+			}
+			p.Counts[t.rule] += steps
+			if k > hardest {
+				hardest = k
+				p.Hardest = t.rule
+			}
+			progress = true
+			break
+		}
+		if !progress {
+			break
+		}
+	}
+	p.Solved = s.emptyL.CountNodes() == 0
+	p.Events = s.explained()
+	return p
+}
+
+// Output of the rules while quiet: the null device and the stdout to restore, with the
+// number of callers still quiet. Solvers running at the same time share the redirect.
+var silence struct {
+	sync.Mutex
+	depth          int
+	devNull, saved *os.File
+}
+
+// Send the output of the rules to the null device. Returns a func to restore it once
+// every caller has restored.
+func quiet() func() {
+	silence.Lock()
+	defer silence.Unlock()
+	if silence.depth == 0 {
+		devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		if err != nil {
+			return func() {}
+		}
+		silence.devNull, silence.saved = devNull, os.Stdout
+		os.Stdout = devNull
+		libcolor.SetOutput(devNull)
+	}
+	silence.depth++
+
+	return func() {
+		silence.Lock()
+		defer silence.Unlock()
+		if silence.depth--; silence.depth == 0 {
+			os.Stdout = silence.saved
+			libcolor.SetOutput(silence.saved)
+			silence.devNull.Close()
+		}
+	}
+}
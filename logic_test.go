@@ -0,0 +1,81 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+func TestSolveLogic(t *testing.T) {
+	s := newSolver()
+
+	tests := []struct {
+		fname, band string
+		hardest     int
+	}{
+		{"difficult1.txt", BandSingles, 3},
+		{"difficult3.txt", BandPairs, 5},
+		{"difficult5.txt", BandXwing, 20},
+		{"expert3.txt", BandGuess, 20},
+	}
+
+	for _, tc := range tests {
+		input, err := readPuzzle(tc.fname)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		prof := s.solveLogic(input)
+		if prof.Band() != tc.band || prof.Hardest != tc.hardest {
+			t.Fatalf("%s: expected %s with rule %d but got %s with rule %d.\n", tc.fname, tc.band, tc.hardest, prof.Band(), prof.Hardest)
+		}
+
+		// every step uses a single technique
+		total := 0
+		for _, cnt := range prof.Counts {
+			total += cnt
+		}
+		if total != len(prof.Steps) {
+			t.Fatalf("%s: expected %d steps but got %d.\n", tc.fname, len(prof.Steps), total)
+		}
+
+		// a step per deduction, not per pass of a rule
+		if len(prof.Steps) != len(prof.Events) {
+			t.Fatalf("%s: expected a step per event, %d, but got %d.\n", tc.fname, len(prof.Events), len(prof.Steps))
+		}
+		if prof.Band() == BandSingles && len(prof.Steps) != CountEmpty(PopulateMat(input)) {
+			t.Fatalf("%s: expected a step per empty cell but got %d.\n", tc.fname, len(prof.Steps))
+		}
+
+		if prof.Solved {
+			sol, _ := SolveUnique(PopulateMat(input))
+			if s.mat != sol {
+				t.Fatalf("%s: expected %s but got %s.\n", tc.fname, MatToString(sol), MatToString(s.mat))
+			}
+		}
+	}
+}
+
+func TestGenerateBand(t *testing.T) {
+	s := newSolver()
+
+	for _, band := range []string{BandSingles, BandPairs, BandXwing, BandGuess} {
+		opts := GenOptions{Rand: rand.New(rand.NewSource(5))}
+		puzzle, sol, prof, err := s.generateBand(opts, band, 500)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := s.solveLogic(MatToString(puzzle)); got.Band() != band || prof.Band() != band {
+			t.Fatalf("Expected %s but got %s.\n", band, got.Band())
+		}
+		if prof.Solved && s.mat != sol {
+			t.Fatalf("%s: expected %s but got %s.\n", band, MatToString(sol), MatToString(s.mat))
+		}
+	}
+
+	if err := checkBand("fish"); err == nil {
+		t.Fatal("Expected error for unknown band.")
+	}
+}
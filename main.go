@@ -4,9 +4,11 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/mjwong/sudoku2/format"
 	. "github.com/mjwong/sudoku2/lib"
 	. "github.com/mjwong/sudoku2/linkedlist"
 	. "github.com/mjwong/sudoku2/matchlist"
@@ -23,17 +25,25 @@ const (
 )
 
 var (
-	iterCnt  int
-	emptyCnt int
-	mat      Intmat
-	mat2     Pmat   // matrix with possible values in empty cells
-	mat3     Intmat // guessed matrix
-	emptyL   *LinkedList
 	debugPtr *bool   = flag.Bool("debug", false, "verbose debug mode")
 	prtLLPtr *bool   = flag.Bool("prtLL", false, "print the linked list of empty cells")
 	verbose  *bool   = flag.Bool("v", false, "Print if the digit(s) are found")
 	rule     *int    = flag.Int("r", 0, "The deffault is 0, which will iterate matrix using linked list.")
 	fnName   *string = flag.String("f", "", "Debug the specified function.")
+	regions  *string = flag.String("regions", "", "Region map file for jigsaw sudoku.")
+	variant  *string = flag.String("variant", "", "Variants, comma separated: x, windoku, centredot, antiknight, antiking, nonconsec.")
+	cages    *string = flag.String("cages", "", "Cage file for killer sudoku.")
+	marks    *string = flag.String("marks", "", "Marks file for Kropki, XV, greater-than and even/odd sudoku.")
+	lines    *string = flag.String("lines", "", "Constraint file for thermometer, arrow, sandwich and little killer sudoku.")
+	multi    *string = flag.String("multi", "", "Multi-grid puzzle file, e.g. Samurai, Twin or Butterfly.")
+	inFile   *string = flag.String("i", "", "Puzzle file, one puzzle per line. Reads one puzzle from stdin if not set.")
+	inFormat *string = flag.String("iformat", "", "Format of the -i file: line, sdm, sdk, ss, opensudoku or grid. Detected if not set.")
+	pmIn     *string = flag.String("pm", "", "Candidates to start from: a candidate grid or a 729-character Sukaku string.")
+	pmOut    *string = flag.String("pmout", "", "Write the candidates at the end, as a Sukaku string if the file ends in .sukaku.")
+	output   *string = flag.String("format", "text", "Output: text, or json for a document per puzzle on a line.")
+	explain  *bool   = flag.Bool("explain", false, "Print the steps of the rules in standard notation, e.g. r3c5=7 (hidden single in box 2).")
+	noColor  *bool   = flag.Bool("no-color", false, "Print without colours. Also off if the output is not a terminal or NO_COLOR is set.")
+	gridFmt  *string = flag.String("grid", GridRows, "Style of the printed grids: rows, box for lines between the blocks, line for 81 characters, or legacy for 0 in empty cells.")
 
 	RuleTable = map[int]string{
 		1:  "Open cell",
@@ -51,102 +61,281 @@ var (
 )
 
 func main() {
+	flag.Parse()
+
+	if err := applyOutputFlags(); err != nil {
+		exitInvalid(err)
+	}
+
+	if *regions != "" {
+		if err := ReadRegions(*regions); err != nil {
+			exitInvalid(err)
+		}
+	}
+
+	if *variant != "" {
+		if err := SetVariants(*variant); err != nil {
+			exitInvalid(err)
+		}
+	}
+
+	if *cages != "" {
+		if err := ReadCages(*cages); err != nil {
+			exitInvalid(err)
+		}
+	}
+
+	if *marks != "" {
+		if err := ReadMarks(*marks); err != nil {
+			exitInvalid(err)
+		}
+	}
+
+	if *lines != "" {
+		if err := ReadConstraints(*lines); err != nil {
+			exitInvalid(err)
+		}
+	}
+
+	if flag.NArg() == 0 {
+		runSolve(nil)
+		return
+	}
+	if !runCommand(flag.Args()) {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", flag.Arg(0))
+		flag.Usage()
+		os.Exit(ExitInvalid)
+	}
+}
+
+// Solve puzzles: sudoku2 [variant flags] solve [-r rule | -rules name] [-explain] [-format json] [-i file | -pm file | file]
+// Reads one puzzle from stdin without a file. The flags of the solver may also come
+// before the command, as without one. Exits with the highest code of the puzzles:
+// 0 if the rules solved them, 1 if not, 2 for invalid input and 3 for a puzzle with
+// more than one solution.
+func runSolve(args []string) {
+	s := newSolver()
+
+	fs := newFlagSet("solve")
+	for _, name := range []string{"r", "explain", "format", "i", "iformat", "pm", "pmout", "multi", "grid", "no-color", "v", "debug", "f", "prtLL"} {
+		f := flag.Lookup(name)
+		fs.Var(f.Value, f.Name, f.Usage)
+	}
+	rules := fs.String("rules", "", "Rules by name instead of -r: "+ruleNamesText()+".")
+	fs.Parse(args)
+
+	if err := applyOutputFlags(); err != nil {
+		exitInvalid(err)
+	}
+	if *rules != "" {
+		if err := setRuleName(*rules); err != nil {
+			exitInvalid(err)
+		}
+	}
+	if fs.NArg() > 1 || fs.NArg() == 1 && *inFile != "" {
+		fs.Usage()
+		os.Exit(ExitInvalid)
+	}
+	if fs.NArg() == 1 {
+		*inFile = fs.Arg(0)
+	}
+
+	if *output == "text" {
+		fmt.Printf("Debug func: %v\n", *fnName)
+	}
+
+	if *multi != "" {
+		if !runMulti(*multi) {
+			os.Exit(ExitUnsolved)
+		}
+		return
+	}
+
+	if *pmIn != "" {
+		m, pm, err := format.ReadCandidatesFile(*pmIn)
+		if err != nil {
+			exitInvalid(err)
+		}
+		os.Exit(s.solveExit(m, &pm, 0))
+	}
+
+	if *inFile == "" {
+		puzzles, err := readStdin(*output == "text")
+		if err != nil {
+			exitInvalid(err)
+		}
+		if len(puzzles) == 1 {
+			os.Exit(s.solveExit(puzzles[0].Mat, nil, 0))
+		}
+		solveAll(s, puzzles)
+	}
+
+	puzzles, err := format.ReadFile(*inFile, *inFormat)
+	if err != nil {
+		exitInvalid(err)
+	}
+	solveAll(s, puzzles)
+}
+
+// Solve each of the puzzles and exit with the highest code
+func solveAll(s *solver, puzzles []Puzzle) {
+	code := ExitSolved
+	for k, p := range puzzles {
+		if *output == "text" {
+			fmt.Printf("Puzzle %d, line %d: %s\n", k+1, p.Line, MatToString(p.Mat))
+		}
+		code = worstExit(code, s.solveExit(p.Mat, nil, p.Line))
+	}
+	os.Exit(code)
+}
+
+// Solve the puzzle and return its exit code. A puzzle without a solution is not solved,
+// as iterMat would not finish.
+func (s *solver) solveExit(m Intmat, cands *Pmat, line int) int {
+	var sol Intmat
+	var cnt int
+	if cands != nil {
+		sol, cnt = SolveUniqueCands(m, *cands)
+	} else {
+		sol, cnt = SolveUnique(m)
+	}
+	if cnt == 0 {
+		fmt.Fprintf(os.Stderr, "%s: puzzle has %s\n", MatToString(m), SolutionsText(cnt))
+		return ExitInvalid
+	}
+	final := s.solve(m, cands, line)
+
+	switch {
+	case cnt != 1:
+		return solutionsExit(cnt)
+	case final != sol:
+		return ExitUnsolved
+	}
+	return ExitSolved
+}
+
+// Solve the puzzle with the rules chosen by -r and print the steps. Starts from the
+// candidates if given, see setCandidates. The line of the puzzle in its file goes in
+// the json report. Returns the grid at the end.
+func (s *solver) solve(m Intmat, cands *Pmat, line int) Intmat {
 	var (
 		start   time.Time
 		elapsed time.Duration
 	)
-	flag.Parse()
-	fmt.Printf("Debug func: %v\n", *fnName)
 
-	mat = PopulateMat(ReadInput())
-	emptyCnt = CountEmpty(mat)
-	fmt.Printf("Empty cells: %d\n", emptyCnt)
+	if *output == "json" {
+		report = newReport(m)
+		report.Line = line
+		restore := quiet()
+		defer func() {
+			restore()
+			report.print(s.finalGrid(), s.iterCnt, time.Since(start))
+			report = nil
+		}()
+	}
+
+	s.iterCnt = 0
+	s.mat3 = Intmat{}
+	s.explaining, s.events = *explain, nil
+	s.clearHistory()
+	s.mat = m
+	s.emptyCnt = CountEmpty(s.mat)
+	fmt.Printf("Empty cells: %d\n", s.emptyCnt)
 	start = time.Now()
-	PrintSudoku(mat)
-	emptyL, mat2 = GetPossibleMat(mat)
+	PrintSudoku(s.mat)
+	if cands != nil {
+		s.setCandidates(m, cands)
+	} else {
+		s.emptyL, s.mat2 = GetPossibleMat(s.mat)
+	}
 	fmt.Println("Starting possibility matrix.")
-	PrintPossibleMat(mat2)
+	PrintPossibleMat(s.mat2)
 
 	if *prtLLPtr {
-		emptyL.ShowAllEmptyCells()
+		s.emptyL.ShowAllEmptyCells()
 	}
 
 	switch *rule {
 	case 0:
 		fmt.Println("Default to iterMat.")
-		mat3 = mat
-		iterMat(emptyL.Head)
-		PrintSudoku(mat3)
-		CheckSums(mat3)
+		s.mat3 = s.mat
+		s.iterMat(s.emptyL.Head)
+		PrintSudoku(s.mat3)
+		CheckSums(s.mat3)
 	case 1:
-		RuleLoop(rule1, RuleTable[1], Zero)
+		s.RuleLoop(s.rule1, RuleTable[1], Zero)
 	case 3:
-		RuleLoop(rule3, RuleTable[3], Zero)
+		s.RuleLoop(s.rule3, RuleTable[3], Zero)
 	case 5:
-		RuleLoop(rule5, RuleTable[5], SameCnt)
+		s.RuleLoop(s.rule5, RuleTable[5], SameCnt)
 	case 13:
-		RuleLoop(rule1, RuleTable[1], Zero)
-		RuleLoop(rule3, RuleTable[3], Zero)
+		s.RuleLoop(s.rule1, RuleTable[1], Zero)
+		s.RuleLoop(s.rule3, RuleTable[3], Zero)
 	case 135:
 		ruleCnt := map[int]int{}
 
 		for {
-			cnt1 := RuleLoop(rule1, RuleTable[1], Zero)
-			cnt3 := RuleLoop(rule3, RuleTable[3], Zero)
-			cntBefore := emptyL.CountElem()
-			cnt5 := RuleLoop(rule5, RuleTable[5], SameCnt)
-			cntAfter := emptyL.CountElem()
+			cnt1 := s.RuleLoop(s.rule1, RuleTable[1], Zero)
+			cnt3 := s.RuleLoop(s.rule3, RuleTable[3], Zero)
+			cntBefore := s.emptyL.CountElem()
+			cnt5 := s.RuleLoop(s.rule5, RuleTable[5], SameCnt)
+			cntAfter := s.emptyL.CountElem()
 			cnt1a := 0
 			if cnt3 > 0 {
-				cnt1a = RuleLoop(rule1, RuleTable[1], Zero)
+				cnt1a = s.RuleLoop(s.rule1, RuleTable[1], Zero)
 			}
 			ruleCnt[1] += cnt1 + cnt1a
 			ruleCnt[3] += cnt3
 			ruleCnt[5] += cnt5
 
-			if cnt1 == 0 && cnt3 == 0 && cntBefore == cntAfter && cnt1a == 0 || emptyL.CountNodes() == 0 {
+			if cnt1 == 0 && cnt3 == 0 && cntBefore == cntAfter && cnt1a == 0 || s.emptyL.CountNodes() == 0 {
 				break
 			}
 		}
 
 		PrintFound([]int{1, 3, 5}, ruleCnt)
-		fmt.Printf("Empty cells : %2d\n", emptyL.CountNodes())
-		fmt.Printf("Rule 1: %d\n", RuleLoop(rule1, RuleTable[1], Zero))
+		fmt.Printf("Empty cells : %2d\n", s.emptyL.CountNodes())
+		fmt.Printf("Rule 1: %d\n", s.RuleLoop(s.rule1, RuleTable[1], Zero))
 
-		if emptyL.CountNodes() == 0 {
-			CheckSums(mat)
+		if s.emptyL.CountNodes() == 0 {
+			CheckSums(s.mat)
 		}
 	case 20:
-		matched20, cnt20, elapsed := rule20()
-		matched20.PrintResult(RuleTable[20])
+		matched20, cnt20, elapsed := s.rule20()
+		printResult(20, matched20, RuleTable[20])
+		reportRule(20, cnt20, elapsed)
 		fmt.Printf("Rule 20: Found %2d %ss. Elapsed time = %v ms\n", cnt20, RuleTable[20], elapsed.Milliseconds())
 	case 99: // run everything including iterMat
 		ruleCnt := map[int]int{}
 		loop := 0
 
 		for {
-			matched1, cnt1, elapsed1 := rule1()
+			matched1, cnt1, elapsed1 := s.rule1()
 			fmt.Printf("After rule1,  found %2d. Empty list count = %2d. Elapsed time = %v us\n",
-				cnt1, emptyL.CountNodes(), elapsed1.Microseconds())
-			matched1.PrintResult("Found open single")
+				cnt1, s.emptyL.CountNodes(), elapsed1.Microseconds())
+			printResult(1, matched1, "Found open single")
+			reportRule(1, cnt1, elapsed1)
 
-			matched3, cnt3, elapsed3 := rule3()
+			matched3, cnt3, elapsed3 := s.rule3()
 			fmt.Printf("After rule3,  found %2d. Empty list count = %2d. Elapsed time = %v us\n",
-				cnt3, emptyL.CountNodes(), elapsed3.Microseconds())
-			matched3.PrintResult("Found hidden single")
+				cnt3, s.emptyL.CountNodes(), elapsed3.Microseconds())
+			printResult(3, matched3, "Found hidden single")
+			reportRule(3, cnt3, elapsed3)
 
-			cntBefore5 := emptyL.CountElem()
-			matched5, cnt5, elapsed5 := rule5()
-			cntAfter5 := emptyL.CountElem()
+			cntBefore5 := s.emptyL.CountElem()
+			matched5, cnt5, elapsed5 := s.rule5()
+			cntAfter5 := s.emptyL.CountElem()
 			fmt.Printf("After rule5,  found %2d. Empty list count = %2d. Elapsed time = %v us\n",
-				cnt5, emptyL.CountNodes(), elapsed5.Microseconds())
+				cnt5, s.emptyL.CountNodes(), elapsed5.Microseconds())
+			reportRule(5, cnt5, elapsed5)
 
-			cntBefore20 := emptyL.CountElem()
-			matched20, cnt20, elapsed20 := rule20()
-			cntAfter20 := emptyL.CountElem()
+			cntBefore20 := s.emptyL.CountElem()
+			matched20, cnt20, elapsed20 := s.rule20()
+			cntAfter20 := s.emptyL.CountElem()
 
 			fmt.Printf("After rule20, found %2d. Empty list count = %2d. Elapsed time = %v us\n",
-				cnt20, emptyL.CountNodes(), elapsed20.Microseconds())
+				cnt20, s.emptyL.CountNodes(), elapsed20.Microseconds())
+			reportRule(20, cnt20, elapsed20)
 
 			if cnt1 <= 0 && cnt3 <= 0 && cntBefore5 == cntAfter5 && cntBefore20 == cntAfter20 {
 				if loop == 2 {
@@ -158,56 +347,79 @@ func main() {
 			ruleCnt[3] += cnt3
 			if cntBefore5 != cntAfter5 {
 				ruleCnt[5] += cnt5
-				matched5.PrintResult("Found hidden single")
+				printResult(5, matched5, "Found naked pair")
 			}
 			if cntBefore20 != cntAfter20 {
 				ruleCnt[20] += cnt20
-				matched20.PrintResult("Found X-wing")
+				printResult(20, matched20, "Found X-wing")
 				color.LightRed.Printf("List count of X-wings: %d\n", matched20.CountNodes())
 			}
 		}
 
-		ecnt := emptyL.CountNodes()
+		ecnt := s.emptyL.CountNodes()
 		fmt.Printf("After rules 1, 3, 5 and 20 have completed. Empty count : %d\n", ecnt)
-		PrintSudoku(mat)
+		PrintSudoku(s.mat)
 
-		if emptyL.CountNodes() > 0 {
-			PrintPossibleMat(mat2)
+		if s.emptyL.CountNodes() > 0 {
+			PrintPossibleMat(s.mat2)
 			// do iterations
-			fmt.Printf("Empty list count before running iterMat = %d.\n", emptyL.CountNodes())
-			PrintPossibleMat(mat2)
-			mat3 = mat
-			iterMat(emptyL.Head)
-			PrintSudoku(mat3)
+			fmt.Printf("Empty list count before running iterMat = %d.\n", s.emptyL.CountNodes())
+			PrintPossibleMat(s.mat2)
+			s.mat3 = s.mat
+			s.iterMat(s.emptyL.Head)
+			PrintSudoku(s.mat3)
 		} else {
-			CheckSums(mat)
+			CheckSums(s.mat)
 		}
 
 		PrintFound([]int{1, 3, 5, 20}, ruleCnt)
-		fmt.Printf("Empty cells : %2d\n", emptyL.CountNodes())
+		fmt.Printf("Empty cells : %2d\n", s.emptyL.CountNodes())
+	}
+
+	if *explain {
+		fmt.Println("Steps:")
+		s.printExplained()
+	}
+
+	if *pmOut != "" {
+		if err := format.WriteCandidatesFile(*pmOut, s.mat, s.mat2); err != nil {
+			log.Fatal(err)
+		}
 	}
 
 	elapsed = time.Since(start)
-	log.Printf("IterMat: Iterations: %d. Empty cells: %d. Sudoku took %v sec\n", iterCnt, CountEmpty(mat), elapsed.Seconds())
+	log.Printf("IterMat: Iterations: %d. Empty cells: %d. Sudoku took %v sec\n", s.iterCnt, CountEmpty(s.mat), elapsed.Seconds())
+	return s.finalGrid()
 }
 
-func RuleLoop(rule fnRule, desc string, exitCond int) int {
+// Grid at the end of solve: the one guessed by iterMat for rules 0 and 99, otherwise
+// the one left by the rules
+func (s *solver) finalGrid() Intmat {
+	if (*rule == 0 || *rule == 99) && s.mat3 != (Intmat{}) {
+		return s.mat3
+	}
+	return s.mat
+}
+
+func (s *solver) RuleLoop(rule fnRule, desc string, exitCond int) int {
 	var (
 		totalCnt  int
 		totalTime time.Duration
 	)
 	exitFor := false
 
-	fnName := GetFunctionName(rule)
+	fnName := funcName(GetFunctionName(rule))
+	n := ruleNumber(rule)
 	for {
-		cntBefore := emptyL.CountNodes()
+		cntBefore := s.emptyL.CountNodes()
 		matched, cnt, elapsed := rule()
 		totalTime += elapsed
-		cntAfter := emptyL.CountNodes()
+		cntAfter := s.emptyL.CountNodes()
 		fmt.Printf("%s: Found %d digits.\n", fnName, cnt)
-		matched.PrintResult(desc)
+		printResult(n, matched, desc)
+		reportRule(n, cnt, elapsed)
 		if *verbose {
-			PrintSudoku(mat)
+			PrintSudoku(s.mat)
 		}
 		switch exitCond {
 		case Zero:
@@ -231,7 +443,7 @@ func RuleLoop(rule fnRule, desc string, exitCond int) int {
 	}
 	fmt.Printf("%s: Total found = %d. Total elapsed time = %v\n", fnName, totalCnt, totalTime)
 	if *verbose {
-		PrintPossibleMat(mat2)
+		PrintPossibleMat(s.mat2)
 	}
 	return totalCnt
 }
@@ -239,11 +451,20 @@ func RuleLoop(rule fnRule, desc string, exitCond int) int {
 func PrintFound(ruleList []int, ruleCounts map[int]int) {
 	for _, v := range ruleList {
 		fmt.Printf("Rule %2d: Found %2d %s\n", v, ruleCounts[v], RuleTable[v])
+		if report != nil {
+			report.RuleCounts[v] = ruleCounts[v]
+		}
 	}
 }
 
+// Name of a func as before the rules became methods of the solver, e.g. main.rule1 for
+// main.(*solver).rule1 or its method value main.(*solver).rule1-fm
+func funcName(name string) string {
+	return strings.TrimSuffix(strings.Replace(name, "(*solver).", "", 1), "-fm")
+}
+
 func DebugFn(skip int) bool {
-	fname := strings.Trim(FuncName(skip), "main.")
+	fname := strings.Trim(funcName(FuncName(skip)), "main.")
 
 	if *fnName == "" {
 		return false
@@ -254,29 +475,30 @@ func DebugFn(skip int) bool {
 	}
 }
 
-func PrepPmat(input string) {
-	mat = PopulateMat(input)
-	emptyCnt = CountEmpty(mat)
+func (s *solver) PrepPmat(input string) {
+	s.mat = PopulateMat(input)
+	s.emptyCnt = CountEmpty(s.mat)
 
-	emptyL, mat2 = GetPossibleMat(mat)
+	s.emptyL, s.mat2 = GetPossibleMat(s.mat)
+	s.clearHistory()
 }
 
-func iterMat(curRCell *Cell) {
+func (s *solver) iterMat(curRCell *Cell) {
 
-	if emptyCnt > 0 {
-		iterCnt++
+	if s.emptyCnt > 0 {
+		s.iterCnt++
 
 		for _, num := range curRCell.Vals {
-			if emptyCnt > 0 {
-				if IsSafe(mat3, curRCell.Row, curRCell.Col, num) {
-					mat3[curRCell.Row][curRCell.Col] = num
-					emptyCnt--
-
-					if emptyCnt > 0 {
-						iterMat(curRCell.Next)
-						if emptyCnt > 0 {
-							mat3[curRCell.Row][curRCell.Col] = 0
-							emptyCnt++
+			if s.emptyCnt > 0 {
+				if IsSafe(s.mat3, curRCell.Row, curRCell.Col, num) {
+					s.mat3[curRCell.Row][curRCell.Col] = num
+					s.emptyCnt--
+
+					if s.emptyCnt > 0 {
+						s.iterMat(curRCell.Next)
+						if s.emptyCnt > 0 {
+							s.mat3[curRCell.Row][curRCell.Col] = 0
+							s.emptyCnt++
 						}
 					} else {
 						color.LightRed.Println("******* Finished *******")
@@ -288,14 +510,19 @@ func iterMat(curRCell *Cell) {
 }
 
 // erase digit from row of possibility matrix in the case of naked pairs
-func eraseDigitsFromRowOfPairs(row, col, col2 int, digits []int) bool {
+func (s *solver) eraseDigitsFromRowOfPairs(row, col, col2 int, digits []int) bool {
 	erased := false
+	s.beginEvent(Event{
+		Technique: TechNakedPair,
+		Digits:    append([]int{}, digits...),
+		Cells:     []Coord{{Row: row, Col: col}, {Row: row, Col: col2}},
+		House:     RowName(row),
+	})
 
 	for c := 0; c < N; c++ {
-		if mat2[row][c] != nil && c != col && c != col2 {
-			if Contains(mat2[row][c], digits[0]) {
-				mat2[row][c] = EraseFromSlice(mat2[row][c], digits[0])
-				emptyL.EraseDigitFromCell(row, c, digits[0])
+		if s.mat2[row][c] != nil && c != col && c != col2 {
+			if Contains(s.mat2[row][c], digits[0]) {
+				s.eraseCandidate(row, c, digits[0])
 				erased = true
 
 				if *verbose {
@@ -304,9 +531,8 @@ func eraseDigitsFromRowOfPairs(row, col, col2 int, digits []int) bool {
 				}
 			}
 
-			if Contains(mat2[row][c], digits[1]) {
-				mat2[row][c] = EraseFromSlice(mat2[row][c], digits[1])
-				emptyL.EraseDigitFromCell(row, c, digits[1])
+			if Contains(s.mat2[row][c], digits[1]) {
+				s.eraseCandidate(row, c, digits[1])
 				erased = true
 
 				if *verbose {
@@ -321,14 +547,19 @@ func eraseDigitsFromRowOfPairs(row, col, col2 int, digits []int) bool {
 }
 
 // erase digit from col of possibility matrix in the case of naked pairs
-func eraseDigitsFromColOfPairs(row, col, row2 int, digits []int) bool {
+func (s *solver) eraseDigitsFromColOfPairs(row, col, row2 int, digits []int) bool {
 	erased := false
+	s.beginEvent(Event{
+		Technique: TechNakedPair,
+		Digits:    append([]int{}, digits...),
+		Cells:     []Coord{{Row: row, Col: col}, {Row: row2, Col: col}},
+		House:     ColName(col),
+	})
 
 	for r := 0; r < N; r++ {
-		if mat2[r][col] != nil && r != row && r != row2 {
-			if Contains(mat2[r][col], digits[0]) {
-				mat2[r][col] = EraseFromSlice(mat2[r][col], digits[0])
-				emptyL.EraseDigitFromCell(r, col, digits[0])
+		if s.mat2[r][col] != nil && r != row && r != row2 {
+			if Contains(s.mat2[r][col], digits[0]) {
+				s.eraseCandidate(r, col, digits[0])
 				erased = true
 
 				if *verbose {
@@ -337,9 +568,8 @@ func eraseDigitsFromColOfPairs(row, col, row2 int, digits []int) bool {
 				}
 			}
 
-			if Contains(mat2[r][col], digits[1]) {
-				mat2[r][col] = EraseFromSlice(mat2[r][col], digits[1])
-				emptyL.EraseDigitFromCell(r, col, digits[1])
+			if Contains(s.mat2[r][col], digits[1]) {
+				s.eraseCandidate(r, col, digits[1])
 				erased = true
 
 				if *verbose {
@@ -354,34 +584,65 @@ func eraseDigitsFromColOfPairs(row, col, row2 int, digits []int) bool {
 }
 
 // erase digit from row of possibility matrix in the case of naked pairs
-func eraseDigitsFromBlkOfPairs(row, col, row2, col2 int, digits []int) bool {
+func (s *solver) eraseDigitsFromBlkOfPairs(row, col, row2, col2 int, digits []int) bool {
 	erased := false
-	startRow := row / SQ * SQ
-	startCol := col / SQ * SQ
+	s.beginEvent(Event{
+		Technique: TechNakedPair,
+		Digits:    append([]int{}, digits...),
+		Cells:     []Coord{{Row: row, Col: col}, {Row: row2, Col: col2}},
+		House:     BoxName(BlkOf(row, col)),
+	})
+
+	for _, cell := range BlkCells(BlkOf(row, col)) {
+		x, y := cell.Row, cell.Col
+		if s.mat2[x][y] != nil && !(x == row && y == col) && !(x == row2 && y == col2) {
+
+			if Contains(s.mat2[x][y], digits[0]) {
+				s.eraseCandidate(x, y, digits[0])
+				erased = true
 
-	for x := startRow; x < startRow+SQ; x++ {
-		for y := startCol; y < startCol+SQ; y++ {
-			if mat2[x][y] != nil && !(x == row && y == col) && !(x == row2 && y == col2) {
+				if *verbose {
+					color.LightMagenta.Printf("Found naked pair (%d,%d) in blk %d. Deleted %d from [%d,%d]\n",
+						digits[0], digits[1], BlkOf(row, col), digits[0], x, y)
+				}
+			}
 
-				if Contains(mat2[x][y], digits[0]) {
-					mat2[x][y] = EraseFromSlice(mat2[x][y], digits[0])
-					emptyL.EraseDigitFromCell(x, y, digits[0])
-					erased = true
+			if Contains(s.mat2[x][y], digits[1]) {
+				s.eraseCandidate(x, y, digits[1])
+				erased = true
 
-					if *verbose {
-						color.LightMagenta.Printf("Found naked pair (%d,%d) in blk [%d,%d]. Deleted %d from [%d,%d]\n",
-							digits[0], digits[1], row/SQ, col/SQ, digits[0], x, y)
-					}
+				if *verbose {
+					color.LightMagenta.Printf("Found naked pair (%d,%d) in blk %d. Deleted %d from [%d,%d]\n",
+						digits[0], digits[1], BlkOf(row, col), digits[1], x, y)
 				}
+			}
+		}
+	}
 
-				if Contains(mat2[x][y], digits[1]) {
-					mat2[x][y] = EraseFromSlice(mat2[x][y], digits[1])
-					emptyL.EraseDigitFromCell(x, y, digits[1])
+	return erased
+}
+
+// erase digits from an extra house (variant) of possibility matrix in the case of naked pairs
+func (s *solver) eraseDigitsFromHouseOfPairs(h, row, col, row2, col2 int, digits []int) bool {
+	erased := false
+	s.beginEvent(Event{
+		Technique: TechNakedPair,
+		Digits:    append([]int{}, digits...),
+		Cells:     []Coord{{Row: row, Col: col}, {Row: row2, Col: col2}},
+		House:     HouseName(h),
+	})
+
+	for _, cell := range Houses[h] {
+		x, y := cell.Row, cell.Col
+		if s.mat2[x][y] != nil && !(x == row && y == col) && !(x == row2 && y == col2) {
+			for _, dig := range digits {
+				if Contains(s.mat2[x][y], dig) {
+					s.eraseCandidate(x, y, dig)
 					erased = true
 
 					if *verbose {
-						color.LightMagenta.Printf("Found naked pair (%d,%d) in blk [%d,%d]. Deleted %d from [%d,%d]\n",
-							digits[0], digits[1], row/SQ, col/SQ, digits[1], x, y)
+						color.LightMagenta.Printf("Found naked pair (%d,%d) in house %d. Deleted %d from [%d,%d]\n",
+							digits[0], digits[1], h, dig, x, y)
 					}
 				}
 			}
@@ -396,15 +657,13 @@ func eraseDigitsFromBlkOfPairs(row, col, row2, col2 int, digits []int) bool {
 // *******************************************************************************************************
 
 // erase digit from row of possibility matrix
-func eraseDigitFromRow(row, col, dig int) bool {
+func (s *solver) eraseDigitFromRow(row, col, dig int) bool {
 	erased := false
 
 	for c := 0; c < N; c++ {
-		if mat2[row][c] != nil && c != col {
-			if Contains(mat2[row][c], dig) {
-				mat2[row][c] = EraseFromSlice(mat2[row][c], dig)
-				// remove this digit from cell at this position of the empty list
-				emptyL.EraseDigitFromCell(row, c, dig)
+		if s.mat2[row][c] != nil && c != col {
+			if Contains(s.mat2[row][c], dig) {
+				s.eraseCandidate(row, c, dig)
 				erased = true
 			}
 		}
@@ -413,15 +672,13 @@ func eraseDigitFromRow(row, col, dig int) bool {
 	return erased
 }
 
-func eraseDigitFromCol(row, col, dig int) bool {
+func (s *solver) eraseDigitFromCol(row, col, dig int) bool {
 	erased := false
 
 	for r := 0; r < N; r++ {
-		if mat2[r][col] != nil && r != row {
-			if Contains(mat2[r][col], dig) {
-				mat2[r][col] = EraseFromSlice(mat2[r][col], dig) // remove from possibility mat
-				// remove this digit from cell at this position of the empty list
-				emptyL.EraseDigitFromCell(r, col, dig)
+		if s.mat2[r][col] != nil && r != row {
+			if Contains(s.mat2[r][col], dig) {
+				s.eraseCandidate(r, col, dig)
 				erased = true
 			}
 		}
@@ -430,21 +687,32 @@ func eraseDigitFromCol(row, col, dig int) bool {
 	return erased
 }
 
-func eraseDigitFromBlk(row, col, dig int) bool {
+func (s *solver) eraseDigitFromBlk(row, col, dig int) bool {
 	erased := false
 
-	startRow := row / SQ * SQ
-	startCol := col / SQ * SQ
+	for _, cell := range BlkCells(BlkOf(row, col)) {
+		x, y := cell.Row, cell.Col
+		if s.mat2[x][y] != nil && x != row && y != col {
+			if Contains(s.mat2[x][y], dig) {
+				s.eraseCandidate(x, y, dig)
+				erased = true
+			}
+		}
+	}
 
-	for x := startRow; x < startRow+SQ; x++ {
-		for y := startCol; y < startCol+SQ; y++ {
-			if mat2[x][y] != nil && x != row && y != col {
-				if Contains(mat2[x][y], dig) {
-					mat2[x][y] = EraseFromSlice(mat2[x][y], dig)
-					// remove this digit from cell at this position of the empty list
-					emptyL.EraseDigitFromCell(x, y, dig)
-					erased = true
-				}
+	return erased
+}
+
+// erase digit from an extra house (variant) of possibility matrix
+func (s *solver) eraseDigitFromHouse(h, row, col, dig int) bool {
+	erased := false
+
+	for _, cell := range Houses[h] {
+		x, y := cell.Row, cell.Col
+		if s.mat2[x][y] != nil && !(x == row && y == col) {
+			if Contains(s.mat2[x][y], dig) {
+				s.eraseCandidate(x, y, dig)
+				erased = true
 			}
 		}
 	}
@@ -452,17 +720,31 @@ func eraseDigitFromBlk(row, col, dig int) bool {
 	return erased
 }
 
+// erase the candidates ruled out by the constraints of a variant, e.g. killer cages
+func (s *solver) eraseByConstraints() bool {
+	erased := false
+
+	for elims := PruneConstraints(s.mat, s.mat2); len(elims) > 0; elims = PruneConstraints(s.mat, s.mat2) {
+		for _, e := range elims {
+			s.eraseCandidate(e.Row, e.Col, e.Dig)
+		}
+		erased = true
+	}
+
+	return erased
+}
+
 // *******************************************************************************************************
 // *                                     start of funcs for X-wing                                       *
 // *******************************************************************************************************
 
 // erase digit from row of possibility matrix. digits is list of nos. to be erased. cols is exception list
-func eraseDigitsFromRowMulti(row int, digits, cols []int) (int, bool) {
+func (s *solver) eraseDigitsFromRowMulti(row int, digits, cols []int) (int, bool) {
 	count := 0
 	erased := false
 
 	for c := 0; c < N; c++ {
-		if mat2[row][c] != nil {
+		if s.mat2[row][c] != nil {
 			inCol := false
 			for _, col := range cols {
 				if c == col {
@@ -472,10 +754,8 @@ func eraseDigitsFromRowMulti(row int, digits, cols []int) (int, bool) {
 
 			if !inCol {
 				for _, dig := range digits {
-					if Contains(mat2[row][c], dig) {
-						mat2[row][c] = EraseFromSlice(mat2[row][c], dig)
-						// remove this digit from cell at this position of the empty list
-						emptyL.EraseDigitFromCell(row, c, dig)
+					if Contains(s.mat2[row][c], dig) {
+						s.eraseCandidate(row, c, dig)
 						erased = true
 						count++
 					}
@@ -488,13 +768,13 @@ func eraseDigitsFromRowMulti(row int, digits, cols []int) (int, bool) {
 }
 
 // erase digit from col of possibility matrix
-func eraseDigitFromColMulti(col, dig int, rows []int) (int, bool) {
+func (s *solver) eraseDigitFromColMulti(col, dig int, rows []int) (int, bool) {
 	debug := DebugFn(3)
 	count := 0
 	erased := false
 
 	for r := 0; r < N; r++ {
-		if mat2[r][col] != nil {
+		if s.mat2[r][col] != nil {
 			inRow := false
 			for _, row := range rows {
 				if r == row {
@@ -503,10 +783,8 @@ func eraseDigitFromColMulti(col, dig int, rows []int) (int, bool) {
 			}
 
 			if !inRow {
-				if Contains(mat2[r][col], dig) {
-					mat2[r][col] = EraseFromSlice(mat2[r][col], dig)
-					// remove this digit from cell at this position of the empty list
-					emptyL.EraseDigitFromCell(r, col, dig)
+				if Contains(s.mat2[r][col], dig) {
+					s.eraseCandidate(r, col, dig)
 					erased = true
 					count++
 
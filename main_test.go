@@ -26,6 +26,8 @@ func TestEmptyCount(t *testing.T) {
 }
 
 func TestGetPossibleMat(t *testing.T) {
+	s := newSolver()
+
 	list := [][]int{
 		{2, 3, 4, 7, 8},
 		{2, 3, 4, 8},
@@ -81,10 +83,10 @@ func TestGetPossibleMat(t *testing.T) {
 	}
 
 	mat := PopulateMat(difficult1)
-	emptyL, mat2 = GetPossibleMat(mat)
-	PrintPossibleMat(mat2)
+	s.emptyL, s.mat2 = GetPossibleMat(mat)
+	PrintPossibleMat(s.mat2)
 
-	currNode := emptyL.Head
+	currNode := s.emptyL.Head
 	if currNode == nil {
 		t.Fatalf("Empty list.")
 	} else {
@@ -100,9 +102,11 @@ func TestGetPossibleMat(t *testing.T) {
 }
 
 func TestDigitNotIn(t *testing.T) {
+	s := newSolver()
+
 	debug := DebugFn(2) // Get name of this caller
 	mat := PopulateMat(difficult1)
-	emptyL, mat2 = GetPossibleMat(mat)
+	s.emptyL, s.mat2 = GetPossibleMat(mat)
 
 	// possibility matrix
 	list := Pmat{
@@ -119,8 +123,8 @@ func TestDigitNotIn(t *testing.T) {
 
 	for i := 0; i < N; i++ {
 		for j := 0; j < N; j++ {
-			if !IntArrayEquals(mat2[i][j], list[i][j]) {
-				t.Fatalf("Expected %v but got %v\n", list[i][j], mat2[i][j])
+			if !IntArrayEquals(s.mat2[i][j], list[i][j]) {
+				t.Fatalf("Expected %v but got %v\n", list[i][j], s.mat2[i][j])
 			}
 		}
 	}
@@ -131,34 +135,36 @@ func TestDigitNotIn(t *testing.T) {
 
 	// digit 1 is found hidden in cell [3,4].
 	// search for digit 1; should find in row 4 and blk [1,1] but not in col 5.
-	if !FindDigitInRow(debug, mat2, 3, 4, 1) {
+	if !FindDigitInRow(debug, s.mat2, 3, 4, 1) {
 		t.Fatalf("Digit should be in row 3.")
 	}
 
-	if FindDigitInCol(debug, mat2, 3, 4, 1) {
+	if FindDigitInCol(debug, s.mat2, 3, 4, 1) {
 		t.Fatalf("Digit should not be in column 4.")
 	}
 
-	if !FindDigitInBlk(debug, mat2, 3, 4, 1) {
+	if !FindDigitInBlk(debug, s.mat2, 3, 4, 1) {
 		t.Fatalf("Digit should be in block [1,1].")
 	}
 }
 
 func TestDelNode(t *testing.T) {
+	s := newSolver()
+
 	input := ".341528699.837645252.948371245.136986895.413737168.524857231.464938672.516249578."
 
-	PrepPmat(input)
+	s.PrepPmat(input)
 
-	currentNode := emptyL.Head
+	currentNode := s.emptyL.Head
 
 	// Fill in digit 7 in [1,1]
-	mat[1][1] = 7
-	mat2[1][1] = nil
+	s.mat[1][1] = 7
+	s.mat2[1][1] = nil
 
-	emptyL.DelNode(currentNode)
+	s.emptyL.DelNode(currentNode)
 
-	if emptyL.CountNodes() != 8 {
-		t.Fatalf("Empty list count should be 8 but got %d.\n", emptyL.CountNodes())
+	if s.emptyL.CountNodes() != 8 {
+		t.Fatalf("Empty list count should be 8 but got %d.\n", s.emptyL.CountNodes())
 	}
 }
 
@@ -180,38 +186,39 @@ func TestIntArrEq(t *testing.T) {
 
 // Rule 8: Hidden Pairs
 func TestRule8(t *testing.T) {
+	s := newSolver()
 
 	input := "43782659168139524729514786336.251978172.893569586731245.396871282971.635716532489"
 
-	ruleTest(t, input, 3, 4, 0)
+	s.ruleTest(t, input, 3, 4, 0)
 
 }
 
-func ruleTest(t *testing.T, input string, rule, empCnt, numFound int) {
+func (s *solver) ruleTest(t *testing.T, input string, rule, empCnt, numFound int) {
 	var (
 		count int
 		desc  string
 	)
-	PrepPmat(input)
+	s.PrepPmat(input)
 
-	if emptyCnt != empCnt {
-		t.Fatalf("Expected %d but got %d.\n", empCnt, emptyCnt)
+	if s.emptyCnt != empCnt {
+		t.Fatalf("Expected %d but got %d.\n", empCnt, s.emptyCnt)
 	}
-	if emptyL.CountNodes() != empCnt {
-		t.Fatalf("Expected %d nodes in empty list but got %d.\n", empCnt, emptyL.CountNodes())
+	if s.emptyL.CountNodes() != empCnt {
+		t.Fatalf("Expected %d nodes in empty list but got %d.\n", empCnt, s.emptyL.CountNodes())
 	}
 
-	PrintPossibleMat(mat2)
-	PrintSudoku(mat)
-	fmt.Printf("Starting empty cells = %d\n", emptyCnt)
+	PrintPossibleMat(s.mat2)
+	PrintSudoku(s.mat)
+	fmt.Printf("Starting empty cells = %d\n", s.emptyCnt)
 
 	switch rule {
 	case 3:
 		desc = RuleTable[3]
-		matched, cnt, elapsed := rule3()
+		matched, cnt, elapsed := s.rule3()
 		digcnt := matched.CountNodes()
 
-		PrintPossibleMat(mat2)
+		PrintPossibleMat(s.mat2)
 
 		color.LightMagenta.Printf("Found: %d digits. Elapsed time = %v ms\n", cnt, elapsed.Milliseconds())
 		if digcnt != cnt {
@@ -222,14 +229,14 @@ func ruleTest(t *testing.T, input string, rule, empCnt, numFound int) {
 
 	case 5:
 		desc = RuleTable[5]
-		matched, cnt, elapsed := rule5()
+		matched, cnt, elapsed := s.rule5()
 		fmt.Printf("Found: %s = %d. Elapsed time = %v ms\n", desc, cnt, elapsed.Milliseconds())
 		matched.PrintResult(desc)
 		count = cnt
 
 	case 8:
 		desc = RuleTable[8]
-		matched, cnt, elapsed := rule8()
+		matched, cnt, elapsed := s.rule8()
 		fmt.Printf("Found: %s = %d. Elapsed time = %v ms\n", desc, cnt, elapsed.Milliseconds())
 		matched.PrintResult(desc)
 	}
@@ -238,12 +245,12 @@ func ruleTest(t *testing.T, input string, rule, empCnt, numFound int) {
 		t.Fatalf("Expected to find %d but got %d counts.\n", numFound, count)
 	}
 
-	PrintPossibleMat(mat2)
-	PrintSudoku(mat)
+	PrintPossibleMat(s.mat2)
+	PrintSudoku(s.mat)
 
-	if emptyCnt == 0 {
+	if s.emptyCnt == 0 {
 		color.Magenta.Println("Finished!")
 	} else {
-		fmt.Printf("Empty cells = %d\n", emptyCnt)
+		fmt.Printf("Empty cells = %d\n", s.emptyCnt)
 	}
 }
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+var marks1Sol = "954831627168275493372964851437658912625719348819342576741586239596123784283497165"
+
+func TestParseMarks(t *testing.T) {
+	rels, pars, err := ParseMarks("# comment\n\nw r1c1 r1c2\n< r2c1 r2c2\ne r3c3 r4c4\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rels) != 2 || rels[1].Kind != Greater || rels[1].A != (Coord{Row: 1, Col: 1}) {
+		t.Fatalf("Expected 2 relations but got %v.\n", rels)
+	}
+	if len(pars) != 2 || !pars[1].Even {
+		t.Fatalf("Expected 2 even cells but got %v.\n", pars)
+	}
+
+	_, _, err = ParseMarks("x r1c1 r1c2\nq r1c3 r1c4\n")
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("Expected error at line 2 but got %v.\n", err)
+	}
+
+	defer ClearConstraints()
+	rels, _, _ = ParseMarks("v r1c1 r2c2\n")
+	if err := SetMarks(rels, nil); err == nil {
+		t.Fatal("Expected error for cells which are not adjacent.")
+	}
+}
+
+func TestMarksPrune(t *testing.T) {
+	s := newSolver()
+
+	defer ClearConstraints()
+
+	// the odd cell [0,2] of the V pair leaves 2 or 4 for [0,1], which the black dot
+	// pairs with 1, 2, 4 or 8 in [0,0]. Nothing is greater than 9 or less than 1.
+	rels, pars, _ := ParseMarks("b r1c1 r1c2\nv r1c2 r1c3\n> r1c4 r1c5\no r1c3\n")
+	if err := SetMarks(rels, pars); err != nil {
+		t.Fatal(err)
+	}
+
+	s.PrepPmat(strings.Repeat(".", 81))
+
+	tests := []struct {
+		row, col int
+		want     []int
+	}{
+		{0, 0, []int{1, 2, 4, 8}},
+		{0, 1, []int{2, 4}},
+		{0, 2, []int{1, 3}},
+		{0, 3, []int{2, 3, 4, 5, 6, 7, 8, 9}},
+		{0, 4, []int{1, 2, 3, 4, 5, 6, 7, 8}},
+	}
+
+	for _, tc := range tests {
+		if !IntArrayEquals(s.mat2[tc.row][tc.col], tc.want) {
+			t.Fatalf("Expected %v at [%d,%d] but got %v.\n", tc.want, tc.row, tc.col, s.mat2[tc.row][tc.col])
+		}
+	}
+}
+
+func TestMarks(t *testing.T) {
+	s := newSolver()
+
+	defer ClearConstraints()
+
+	if err := ReadMarks("marks1_marks.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile("marks1.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.PrepPmat(strings.TrimSpace(string(b)))
+
+	s.RuleLoop(s.rule3, RuleTable[3], Zero)
+	s.RuleLoop(s.rule1, RuleTable[1], Zero)
+
+	s.mat3 = s.mat
+	s.iterMat(s.emptyL.Head)
+
+	if MatToString(s.mat3) != marks1Sol {
+		t.Fatalf("Expected %s but got %s.\n", marks1Sol, MatToString(s.mat3))
+	}
+
+	if !ConstraintsHold(s.mat3) {
+		t.Fatal("Expected marks to hold.")
+	}
+}
@@ -82,6 +82,19 @@ func (p *Matchlist) CountNodes() int {
 	return count
 }
 
+// The cells of each match in order, with copies of their values
+func (p *Matchlist) Matches() [][]RCell {
+	var list [][]RCell
+	for currN := p.Head; currN != nil; currN = currN.Next {
+		var arr []RCell
+		for _, c := range currN.Arr {
+			arr = append(arr, RCell{Row: c.Row, Col: c.Col, Vals: append([]int(nil), c.Vals...)})
+		}
+		list = append(list, arr)
+	}
+	return list
+}
+
 func (p *Matchlist) ContainsPair(arrRCell []RCell) bool {
 	cNode := p.Head
 
@@ -143,6 +156,18 @@ func ContainsRCell(a []RCell, b RCell) bool {
 }
 
 func (p *Matchlist) PrintResult(desc string) {
+	var groups [][]CellDigits
+	for n := p.Head; n != nil; n = n.Next {
+		var g []CellDigits
+		for _, v := range n.Arr {
+			g = append(g, CellDigits{Row: v.Row, Col: v.Col, Vals: v.Vals})
+		}
+		groups = append(groups, g)
+	}
+	if PrintCellGroups(desc, groups) {
+		return
+	}
+
 	currNode := p.Head
 	for currNode != nil {
 		color.LightCyan.Printf("%s: %v at [%d,%d]", desc, currNode.Arr[0].Vals,
@@ -43,3 +43,26 @@ func TestAddRCell(t *testing.T) {
 		t.Fatalf("Expected 4 but got %d.\n", len(arr))
 	}
 }
+
+func TestMatches(t *testing.T) {
+	cell1 := &Cell{Row: 4, Col: 5, Vals: []int{2, 8}}
+	cell2 := &Cell{Row: 6, Col: 7, Vals: []int{2, 8}}
+
+	matched := &Matchlist{}
+	matched.AddCell(cell1, 8)
+	matched.AddRNode(AddRCell(nil, cell1, cell2))
+
+	list := matched.Matches()
+	if len(list) != 2 || len(list[0]) != 1 || len(list[1]) != 2 {
+		t.Fatalf("Expected matches of 1 and 2 cells but got %v.\n", list)
+	}
+	if list[0][0].Row != 4 || list[0][0].Vals[0] != 8 || list[1][1].Col != 7 {
+		t.Fatalf("Expected [4,5] 8 and [6,7] but got %v.\n", list)
+	}
+
+	// the values are copies
+	cell2.Vals[0] = 9
+	if list[1][1].Vals[0] != 2 {
+		t.Fatalf("Expected 2 but got %d.\n", list[1][1].Vals[0])
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+// Minimize a puzzle: sudoku2 [variant flags] minimize [-sym rot180] [-seed 42] [-report] [file]
+// The puzzle is read from the file or stdin. The minimal puzzle is printed on a line in
+// the input format of the solver, the report on lines starting with #.
+func runMinimize(args []string) {
+	fs := newFlagSet("minimize")
+	sym := fs.String("sym", SymNone, "Symmetry to preserve: none, rot180, rot90, diagonal, antidiagonal, horizontal, vertical.")
+	seed := fs.Int64("seed", 0, "Random seed for the order of removal, 0 for row order.")
+	report := fs.Bool("report", false, "Report the redundant clues of the original puzzle.")
+	fs.Parse(args)
+
+	input, err := readPuzzle(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(ExitInvalid)
+	}
+	m := PopulateMat(input)
+	if cnt := CountSolutions(m, 2); cnt != 1 {
+		fmt.Fprintf(os.Stderr, "puzzle has %s\n", SolutionsText(cnt))
+		os.Exit(solutionsExit(cnt))
+	}
+
+	var r *rand.Rand
+	if *seed != 0 {
+		r = rand.New(rand.NewSource(*seed))
+	}
+	minimal, err := Minimize(m, *sym, r)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(ExitInvalid)
+	}
+
+	fmt.Println(MatToString(minimal))
+	if *report {
+		var list []string
+		for _, c := range RedundantClues(m) {
+			list = append(list, fmt.Sprintf("r%dc%d=%d", c.Row+1, c.Col+1, m[c.Row][c.Col]))
+		}
+		fmt.Printf("# Clues: %d -> %d\n", N*N-CountEmpty(m), N*N-CountEmpty(minimal))
+		fmt.Printf("# Redundant clues in the original: %d %s\n", len(list), strings.Join(list, " "))
+	}
+}
+
+// Read the first puzzle of the file, or of stdin if fname is empty. Comments and blank
+// lines are skipped, see ReadPuzzles.
+func readPuzzle(fname string) (string, error) {
+	f := os.Stdin
+	if fname != "" {
+		var err error
+		if f, err = os.Open(fname); err != nil {
+			return "", err
+		}
+		defer f.Close()
+	}
+
+	list, err := ReadPuzzles(f)
+	if len(list) > 0 {
+		return MatToString(list[0].Mat), nil
+	}
+	if err == nil {
+		err = fmt.Errorf("no puzzle")
+	}
+	return "", err
+}
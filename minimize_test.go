@@ -0,0 +1,84 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+func TestRedundantClues(t *testing.T) {
+	input, err := readPuzzle("expert1.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list := RedundantClues(PopulateMat(input))
+	if len(list) != 9 || list[0] != (Coord{Row: 0, Col: 4}) {
+		t.Fatalf("Expected 9 redundant clues from [0,4] but got %v.\n", list)
+	}
+}
+
+func TestMinimize(t *testing.T) {
+	tests := []struct {
+		fname, sym string
+		seed       int64
+	}{
+		{"expert1.txt", SymNone, 0},
+		{"expert1.txt", SymNone, 35},
+		{"difficult1.txt", SymRot180, 0},
+	}
+
+	for _, tc := range tests {
+		input, err := readPuzzle(tc.fname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := PopulateMat(input)
+
+		var r *rand.Rand
+		if tc.seed != 0 {
+			r = rand.New(rand.NewSource(tc.seed))
+		}
+		minimal, err := Minimize(m, tc.sym, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sol, cnt := SolveUnique(minimal)
+		orig, _ := SolveUnique(m)
+		if cnt != 1 || sol != orig {
+			t.Fatalf("%s: expected the original solution but got %d solutions.\n", tc.fname, cnt)
+		}
+		if !HasSymmetry(minimal, tc.sym) {
+			t.Fatalf("%s: expected %s symmetry in %s.\n", tc.fname, tc.sym, MatToString(minimal))
+		}
+
+		// every clue (group of symmetric clues) is needed
+		for i := 0; i < N; i++ {
+			for j := 0; j < N; j++ {
+				if minimal[i][j] == 0 {
+					continue
+				}
+				if minimal[i][j] != m[i][j] {
+					t.Fatalf("%s: clue [%d,%d] is not in the original.\n", tc.fname, i, j)
+				}
+				less := minimal
+				for _, c := range SymmetricCells(tc.sym, i, j) {
+					less[c.Row][c.Col] = 0
+				}
+				if CountSolutions(less, 2) == 1 {
+					t.Fatalf("%s: clue [%d,%d] is redundant.\n", tc.fname, i, j)
+				}
+			}
+		}
+	}
+
+	input, _ := readPuzzle("difficult1.txt")
+	if _, err := Minimize(PopulateMat(input), SymRot90, nil); err == nil {
+		t.Fatal("Expected error for missing symmetry.")
+	}
+	if _, err := Minimize(PopulateMat(".........."+input[10:]), SymNone, nil); err == nil {
+		t.Fatal("Expected error for more than one solution.")
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	. "github.com/mjwong/sudoku2/lib"
+	. "github.com/mjwong/sudoku2/linkedlist"
+	"gopkg.in/gookit/color.v1"
+)
+
+// Solve a multi-grid puzzle such as Samurai. The rules run on each grid in turn and
+// the digits found are copied into the cells it shares with the other grids, until no
+// grid makes progress. Any cells left are solved by backtracking over the combined layout.
+func (s *solver) solveMulti(mg *MultiGrid) bool {
+	for pass := 1; ; pass++ {
+		found := 0
+		for g := range mg.Offsets {
+			s.mat = mg.Grid(g)
+			s.emptyCnt = CountEmpty(s.mat)
+			if s.emptyCnt == 0 {
+				continue
+			}
+			s.emptyL, s.mat2 = GetPossibleMat(s.mat)
+
+			s.RuleLoop(s.rule1, RuleTable[1], Zero)
+			s.RuleLoop(s.rule3, RuleTable[3], Zero)
+
+			cnt, err := mg.SetGrid(g, s.mat)
+			if err != nil {
+				color.LightRed.Println(err)
+				return false
+			}
+			found += cnt
+		}
+		fmt.Printf("Multi-grid pass %d: found %d digits. Empty cells: %d\n", pass, found, mg.CountEmpty())
+
+		if found == 0 || mg.CountEmpty() == 0 {
+			break
+		}
+	}
+
+	if mg.CountEmpty() > 0 {
+		ok, iter := mg.Solve()
+		s.iterCnt += iter
+		if !ok {
+			return false
+		}
+	}
+	return mg.Solved()
+}
+
+// Read, solve and print a multi-grid puzzle file. Returns false if not solved.
+func runMulti(fname string) bool {
+	s := newSolver()
+
+	mg, err := ReadMultiGrid(fname)
+	if err != nil {
+		exitInvalid(err)
+	}
+
+	fmt.Printf("Grids: %d. Empty cells: %d\n", len(mg.Offsets), mg.CountEmpty())
+	mg.Print()
+
+	start := time.Now()
+	solved := s.solveMulti(mg)
+	if solved {
+		color.Bold.Println("Finished!")
+	} else {
+		color.LightRed.Println("No solution.")
+	}
+	mg.Print()
+
+	log.Printf("Multi-grid: Iterations: %d. Empty cells: %d. Sudoku took %v sec\n", s.iterCnt, mg.CountEmpty(), time.Since(start).Seconds())
+	return solved
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+var (
+	samurai1Sol = "962354187231456789384671592674389125175892634895127346759238461312864957628417953547291638431569278968735412247986315246789543261516743829137456912873893125746589123678594172953648583764291694812375346789251378964123578589126437695812579346127345968421537468129251863794173645892863497512256981437794251386489732615415632879345296781632978145628317954978514623791854263"
+	twin1Sol    = "962354187384671592175892634759238461628417953431569278247986315246789516743829137456893125746589123172953648583764291694812375251378964437695812968421537"
+)
+
+// digits of the combined layout, row by row
+func multiToString(mg *MultiGrid) string {
+	return strings.NewReplacer(" ", "", "\n", "").Replace(mg.String())
+}
+
+func TestParseMultiGrid(t *testing.T) {
+	mg, err := ParseMultiGrid("layout samurai\n" + strings.Repeat(strings.Repeat(".", 21)+"\n", 21))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mg.Rows != 21 || mg.Cols != 21 || len(mg.Offsets) != 5 {
+		t.Fatalf("Expected 5 grids in 21x21 but got %d in %dx%d.\n", len(mg.Offsets), mg.Rows, mg.Cols)
+	}
+	// the gaps between the outer grids are not cells
+	if mg.Cells[0][10] != -1 || mg.Cells[10][10] != 0 || mg.CountEmpty() != 369 {
+		t.Fatalf("Expected 369 cells but got %d.\n", mg.CountEmpty())
+	}
+
+	// blank lines after the rows
+	b, err := os.ReadFile("samurai1.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseMultiGrid(string(b) + "\n \n"); err != nil {
+		t.Fatalf("Expected the blank lines after the rows to be skipped but got %v.\n", err)
+	}
+
+	bad := map[string]string{
+		"layout hexagon\n":                                         "line 1",
+		"grid 0 0\ngrid 4 4\n":                                     "whole blocks",
+		"grid 0 0\n" + strings.Repeat(".\n", 9):                    "line 2",
+		"grid 0 0\n........x\n" + strings.Repeat(".........\n", 8): "line 2",
+	}
+	for s, want := range bad {
+		if _, err := ParseMultiGrid(s); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("Expected error with %q for %q but got %v.\n", want, s, err)
+		}
+	}
+}
+
+func TestMultiGridShared(t *testing.T) {
+	mg, err := ReadMultiGrid("twin1.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// [6,6] of grid 1 is [0,0] of grid 2
+	m := mg.Grid(0)
+	m[6][6] = 2
+	if cnt, err := mg.SetGrid(0, m); cnt != 1 || err != nil {
+		t.Fatalf("Expected 1 cell set but got %d, %v.\n", cnt, err)
+	}
+	if mg.Grid(1)[0][0] != 2 {
+		t.Fatalf("Expected 2 in grid 2 but got %d.\n", mg.Grid(1)[0][0])
+	}
+
+	m = mg.Grid(1)
+	m[0][0] = 3
+	if _, err := mg.SetGrid(1, m); err == nil {
+		t.Fatal("Expected error for conflicting shared cell.")
+	}
+}
+
+func TestMultiGrid(t *testing.T) {
+	s := newSolver()
+
+	tests := []struct {
+		fname, sol string
+	}{
+		{"twin1.txt", twin1Sol},
+		{"samurai1.txt", samurai1Sol},
+	}
+
+	for _, tc := range tests {
+		mg, err := ReadMultiGrid(tc.fname)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !s.solveMulti(mg) {
+			t.Fatalf("%s: expected a solution.\n", tc.fname)
+		}
+		if got := multiToString(mg); got != tc.sol {
+			t.Fatalf("%s: expected %s but got %s.\n", tc.fname, tc.sol, got)
+		}
+
+		// a row of the solution cleared has one solution, and the count leaves it cleared
+		for c := 0; c < N; c++ {
+			mg.Cells[0][c] = 0
+		}
+		if cnt := mg.CountSolutions(2); cnt != 1 || mg.CountEmpty() != N {
+			t.Fatalf("%s: expected 1 solution with %d cells empty but got %d with %d.\n", tc.fname, N, cnt, mg.CountEmpty())
+		}
+	}
+}
@@ -8,44 +8,47 @@ import (
 )
 
 func TestRule3n5(t *testing.T) {
-	PrepPmat(difficult3)
-	totCnt := RuleLoop(rule3, RuleTable[3], Zero)
+	s := newSolver()
+
+	s.PrepPmat(difficult3)
+	totCnt := s.RuleLoop(s.rule3, RuleTable[3], Zero)
 	if totCnt != 29 {
 		t.Fatalf("Expected to find 29 but got %d counts.\n", totCnt)
 	}
 
 	fmt.Println("Starting possible matrix for Rule 5.")
-	PrintPossibleMat(mat2)
+	PrintPossibleMat(s.mat2)
 
 	input := "142.73...597.462.3863.52...31852469772639.4.545976.32.6.54391.293128....2.461..39"
-	ruleTest(t, input, 5, 23, 9)
+	s.ruleTest(t, input, 5, 23, 9)
 
-	cnt := RuleLoop(rule1, RuleTable[1], Zero)
+	cnt := s.RuleLoop(s.rule1, RuleTable[1], Zero)
 	if cnt != 23 {
 		t.Fatalf("Expected 23 but got %d\n", cnt)
 	}
 
-	if !CheckSums(mat) {
+	if !CheckSums(s.mat) {
 		t.Fatal("Expected to be solved")
-		PrintSudoku(mat)
+		PrintSudoku(s.mat)
 	}
 }
 
 func TestRule135(t *testing.T) { // difficult5.txt
+	s := newSolver()
 	ruleCnt := map[int]int{}
-	PrepPmat(difficult5)
+	s.PrepPmat(difficult5)
 
-	startCnt := emptyL.CountNodes()
+	startCnt := s.emptyL.CountNodes()
 	if startCnt != 54 {
 		t.Fatalf("Expected to find 54 but got %d counts.\n", startCnt)
 	}
 	fmt.Printf("Starting empty count: %d\n", startCnt)
 
-	ruleCnt[1] = RuleLoop(rule1, RuleTable[1], Zero)
-	ruleCnt[3] = RuleLoop(rule3, RuleTable[3], Zero)
-	cntBefore := emptyL.CountElem()
-	ruleCnt[5] = RuleLoop(rule5, RuleTable[5], SameCnt)
-	cntAfter := emptyL.CountElem()
+	ruleCnt[1] = s.RuleLoop(s.rule1, RuleTable[1], Zero)
+	ruleCnt[3] = s.RuleLoop(s.rule3, RuleTable[3], Zero)
+	cntBefore := s.emptyL.CountElem()
+	ruleCnt[5] = s.RuleLoop(s.rule5, RuleTable[5], SameCnt)
+	cntAfter := s.emptyL.CountElem()
 
 	if ruleCnt[3] != 11 {
 		t.Fatalf("Expect to find 11 hidden singles but got %d.\n", ruleCnt[3])
@@ -53,34 +56,36 @@ func TestRule135(t *testing.T) { // difficult5.txt
 
 	PrintFound([]int{1, 3, 5}, ruleCnt)
 	fmt.Printf("Count before and after Rule 5: %d, %d.\n", cntBefore, cntAfter)
-	fmt.Printf("Empty cells : %2d\n", emptyL.CountNodes())
+	fmt.Printf("Empty cells : %2d\n", s.emptyL.CountNodes())
 
-	if emptyL.CountNodes() == 0 {
-		if !CheckSums(mat) {
+	if s.emptyL.CountNodes() == 0 {
+		if !CheckSums(s.mat) {
 			t.Fatal("Expected to be solved")
-			PrintSudoku(mat)
+			PrintSudoku(s.mat)
 		}
 	}
 }
 
 func TestRule_135a(t *testing.T) {
+	s := newSolver()
+
 	ruleCnt := map[int]int{}
 
 	// mid-way through difficult5.txt
 	input := ".4...8..37..4.382..3..16..49.4.6.38..6..3.49..23.4...64..12.63.31268..45...3.4.1."
-	PrepPmat(input)
+	s.PrepPmat(input)
 
-	startCnt := emptyL.CountNodes()
+	startCnt := s.emptyL.CountNodes()
 	if startCnt != 41 {
 		t.Fatalf("Expected to find 41 but got %d counts.\n", startCnt)
 	}
 	fmt.Printf("Starting empty count: %d\n", startCnt)
 
-	ruleCnt[1] = RuleLoop(rule1, RuleTable[1], Zero)
-	ruleCnt[3] = RuleLoop(rule3, RuleTable[3], Zero)
-	cntBefore := emptyL.CountElem()
-	ruleCnt[5] = RuleLoop(rule5, RuleTable[5], SameCnt)
-	cntAfter := emptyL.CountElem()
+	ruleCnt[1] = s.RuleLoop(s.rule1, RuleTable[1], Zero)
+	ruleCnt[3] = s.RuleLoop(s.rule3, RuleTable[3], Zero)
+	cntBefore := s.emptyL.CountElem()
+	ruleCnt[5] = s.RuleLoop(s.rule5, RuleTable[5], SameCnt)
+	cntAfter := s.emptyL.CountElem()
 
 	if ruleCnt[3] != 41 {
 		t.Fatalf("Expect to find 41 hidden singles but got %d.\n", ruleCnt[3])
@@ -88,17 +93,19 @@ func TestRule_135a(t *testing.T) {
 
 	PrintFound([]int{1, 3, 5}, ruleCnt)
 	fmt.Printf("Count before and after Rule 5: %d, %d.\n", cntBefore, cntAfter)
-	fmt.Printf("Empty cells : %2d\n", emptyL.CountNodes())
+	fmt.Printf("Empty cells : %2d\n", s.emptyL.CountNodes())
 
-	if emptyL.CountNodes() == 0 {
-		if !CheckSums(mat) {
+	if s.emptyL.CountNodes() == 0 {
+		if !CheckSums(s.mat) {
 			t.Fatal("Expected to be solved")
-			PrintSudoku(mat)
+			PrintSudoku(s.mat)
 		}
 	}
 }
 
 func TestRule_135c(t *testing.T) {
+	s := newSolver()
+
 	ruleCnt := map[int]int{}
 
 	// mid-way through difficult5.txt
@@ -109,20 +116,20 @@ func TestRule_135c(t *testing.T) {
 	// because the edge cells of the rectangle occupies row 1 and row 3.
 	//                        *
 	input := ".4...8..37..4.38...3..16..49.4.6.38..6..3.49..23.4...64..12..3.31268..45...3.4.1."
-	PrepPmat(input)
+	s.PrepPmat(input)
 
-	startCnt := emptyL.CountNodes()
+	startCnt := s.emptyL.CountNodes()
 	if startCnt != 43 {
 		t.Fatalf("Expected to find 43 but got %d counts.\n", startCnt)
 	}
 	fmt.Printf("Starting empty count: %d\n", startCnt)
 
 	for {
-		cnt1 := RuleLoop(rule1, RuleTable[1], Zero)
-		cnt3 := RuleLoop(rule3, RuleTable[3], Zero)
-		cntBefore := emptyL.CountElem()
-		cnt5 := RuleLoop(rule5, RuleTable[5], SameCnt)
-		cntAfter := emptyL.CountElem()
+		cnt1 := s.RuleLoop(s.rule1, RuleTable[1], Zero)
+		cnt3 := s.RuleLoop(s.rule3, RuleTable[3], Zero)
+		cntBefore := s.emptyL.CountElem()
+		cnt5 := s.RuleLoop(s.rule5, RuleTable[5], SameCnt)
+		cntAfter := s.emptyL.CountElem()
 
 		ruleCnt[1] += cnt1
 		ruleCnt[3] += cnt3
@@ -135,12 +142,12 @@ func TestRule_135c(t *testing.T) {
 	}
 
 	PrintFound([]int{1, 3, 5}, ruleCnt)
-	fmt.Printf("Empty cells : %2d\n", emptyL.CountNodes())
+	fmt.Printf("Empty cells : %2d\n", s.emptyL.CountNodes())
 
-	if emptyL.CountNodes() == 0 {
-		if !CheckSums(mat) {
+	if s.emptyL.CountNodes() == 0 {
+		if !CheckSums(s.mat) {
 			t.Fatal("Expected to be solved")
-			PrintSudoku(mat)
+			PrintSudoku(s.mat)
 		}
 	}
 }
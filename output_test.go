@@ -0,0 +1,119 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	libcolor "github.com/gookit/color"
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+// Output of fn on stdout
+func captureOutput(t *testing.T, fn func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	libcolor.SetOutput(w)
+	fn()
+	os.Stdout = stdout
+	libcolor.SetOutput(stdout)
+	w.Close()
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestGridStyles(t *testing.T) {
+	const puzzle = "...15....91..764..5.6.4.3........69.6..5.4..7.71........7.3.9.6..386..15....95..."
+	m := PopulateMat(puzzle)
+
+	SetColor(false)
+	defer SetColor(true)
+	defer SetGridStyle(GridRows)
+
+	tests := []struct {
+		style string
+		lines []string // first lines of the output
+	}{
+		{GridRows, []string{". . . 1 5 . . . . ", "9 1 . . 7 6 4 . . "}},
+		{GridLegacy, []string{"0 0 0 1 5 0 0 0 0 ", "9 1 0 0 7 6 4 0 0 "}},
+		{GridLine, []string{puzzle}},
+		{GridBox, []string{
+			"┌───────┬───────┬───────┐",
+			"│ . . . │ 1 5 . │ . . . │",
+			"│ 9 1 . │ . 7 6 │ 4 . . │",
+			"│ 5 . 6 │ . 4 . │ 3 . . │",
+			"├───────┼───────┼───────┤",
+		}},
+	}
+	for _, tc := range tests {
+		if err := SetGridStyle(tc.style); err != nil {
+			t.Fatal(err)
+		}
+		out := captureOutput(t, func() { PrintSudoku(m) })
+		if strings.Contains(out, "\x1b[") {
+			t.Fatalf("%s: expected no colours but got %q.\n", tc.style, out)
+		}
+		lines := strings.Split(out, "\n")
+		for k, l := range tc.lines {
+			if lines[k] != l {
+				t.Fatalf("%s: expected line %d %q but got %q.\n", tc.style, k+1, l, lines[k])
+			}
+		}
+	}
+
+	if err := SetGridStyle("fancy"); err == nil {
+		t.Fatal("Expected an error for an unknown grid style.")
+	}
+}
+
+func TestCandidateStyles(t *testing.T) {
+	var pm Pmat
+	pm[0][0] = []int{1, 3, 7}
+	pm[0][4] = []int{2}
+	pm[8][8] = []int{4, 9}
+
+	SetColor(false)
+	defer SetColor(true)
+	defer SetGridStyle(GridRows)
+
+	tests := []struct {
+		style string
+		lines []string // first lines of the output
+	}{
+		{GridRows, []string{" 137 . . | . 2 . | . . .", " .   . . | . . . | . . ."}},
+		{GridLine, []string{"137 . . . 2 " + strings.Repeat(". ", 75) + "49"}},
+		{GridBox, []string{"┌─────────┬───────┬────────┐", "│ 137 . . │ . 2 . │ . . .  │"}},
+		{GridLegacy, []string{strings.Repeat("-", 199), "|1,3,7                |                     |"}},
+	}
+	for _, tc := range tests {
+		if err := SetGridStyle(tc.style); err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(captureOutput(t, func() { PrintPossibleMat(pm) }), "\n")
+		for k, l := range tc.lines {
+			if !strings.HasPrefix(lines[k], l) {
+				t.Fatalf("%s: expected line %d %q but got %q.\n", tc.style, k+1, l, lines[k])
+			}
+		}
+	}
+
+	// lists of cells
+	groups := [][]CellDigits{{{Row: 2, Col: 4, Vals: []int{7}}}, {{Row: 0, Col: 0, Vals: []int{1, 3}}, {Row: 0, Col: 3, Vals: []int{1, 3}}}}
+	SetGridStyle(GridLine)
+	if out := captureOutput(t, func() { PrintCellGroups("Pairs", groups) }); out != "Pairs: r3c5{7}; r1c1{13} r1c4{13}\n" {
+		t.Fatalf("Expected the cells on a line but got %q.\n", out)
+	}
+	SetGridStyle(GridRows)
+	if PrintCellGroups("Pairs", groups) {
+		t.Fatal("Expected the rows style to leave the list to print itself.")
+	}
+}
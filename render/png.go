@@ -0,0 +1,56 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+// Digits 1 to 9 of a 5x7 bitmap font, a row of 5 bits per byte
+var glyphs = [N + 1][7]byte{
+	1: {0x04, 0x0c, 0x04, 0x04, 0x04, 0x04, 0x0e},
+	2: {0x0e, 0x11, 0x01, 0x02, 0x04, 0x08, 0x1f},
+	3: {0x1f, 0x02, 0x04, 0x02, 0x01, 0x11, 0x0e},
+	4: {0x02, 0x06, 0x0a, 0x12, 0x1f, 0x02, 0x02},
+	5: {0x1f, 0x10, 0x1e, 0x01, 0x01, 0x11, 0x0e},
+	6: {0x06, 0x08, 0x10, 0x1e, 0x11, 0x11, 0x0e},
+	7: {0x1f, 0x01, 0x02, 0x04, 0x08, 0x08, 0x08},
+	8: {0x0e, 0x11, 0x11, 0x0e, 0x11, 0x11, 0x0e},
+	9: {0x0e, 0x11, 0x11, 0x0f, 0x01, 0x02, 0x0c},
+}
+
+// Write the drawing as PNG
+func PNG(w io.Writer, m Intmat, opts Options) error {
+	d := layout(m, opts)
+	img := image.NewRGBA(image.Rect(0, 0, d.width, d.height))
+
+	for _, r := range d.rects {
+		fill(img, r.x, r.y, r.w, r.h, r.c)
+	}
+	for _, t := range d.texts {
+		drawDigit(img, t)
+	}
+	return png.Encode(w, img)
+}
+
+func fill(img *image.RGBA, x, y, w, h int, c color.RGBA) {
+	draw.Draw(img, image.Rect(x, y, x+w, y+h), &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
+
+// Draw the glyph of the digit scaled to the size of the text, a dot per square
+func drawDigit(img *image.RGBA, t text) {
+	dot := maxInt(1, t.size/7)
+	x0, y0 := t.x-5*dot/2, t.y-7*dot/2
+
+	for row, bits := range glyphs[t.dig] {
+		for col := 0; col < 5; col++ {
+			if bits&(0x10>>col) != 0 {
+				fill(img, x0+col*dot, y0+row*dot, dot, dot, t.c)
+			}
+		}
+	}
+}
@@ -0,0 +1,209 @@
+// Package render draws grids as SVG or PNG images: the clues and the solved digits, the
+// candidates of the empty cells, and the cells and candidates of a step.
+package render
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+// Options of a drawing. The zero value draws the digits of the grid as clues.
+type Options struct {
+	CellSize int     // pixels per cell, 60 if 0
+	Givens   *Intmat // the clues, other digits are drawn as solved. All are clues if nil.
+	Cands    *Pmat   // candidates drawn small in the empty cells
+	Step     *Event  // step to highlight: its pattern cells, placements and eliminations
+}
+
+// Colours of the drawing
+var (
+	colorLine      = color.RGBA{0x00, 0x00, 0x00, 0xff}
+	colorThin      = color.RGBA{0x99, 0x99, 0x99, 0xff}
+	colorGiven     = color.RGBA{0x00, 0x00, 0x00, 0xff}
+	colorSolved    = color.RGBA{0x1a, 0x5f, 0xb4, 0xff}
+	colorCand      = color.RGBA{0x55, 0x55, 0x55, 0xff}
+	colorPattern   = color.RGBA{0xff, 0xf3, 0xa0, 0xff}
+	colorPlacement = color.RGBA{0xc8, 0xf0, 0xc8, 0xff}
+	colorElim      = color.RGBA{0xf4, 0xa0, 0xa0, 0xff}
+	colorElimText  = color.RGBA{0xc0, 0x1c, 0x28, 0xff}
+	colorPaper     = color.RGBA{0xff, 0xff, 0xff, 0xff}
+)
+
+// Shapes of a drawing, shared by the SVG and the PNG writers
+type rect struct {
+	x, y, w, h int
+	c          color.RGBA
+}
+
+// Digit centred on x, y, size pixels high
+type text struct {
+	x, y, size, dig int
+	c               color.RGBA
+}
+
+type drawing struct {
+	width, height int
+	rects         []rect // filled in order, lines are thin rects
+	texts         []text
+}
+
+const margin = 10
+
+// Lay out the grid as shapes
+func layout(m Intmat, opts Options) drawing {
+	cs := opts.CellSize
+	if cs <= 0 {
+		cs = 60
+	}
+	size := N*cs + 2*margin
+	d := drawing{width: size, height: size}
+	d.rects = append(d.rects, rect{0, 0, size, size, colorPaper})
+
+	cellX := func(col int) int { return margin + col*cs }
+	cellY := func(row int) int { return margin + row*cs }
+	candXY := func(row, col, dig int) (int, int) {
+		return cellX(col) + ((dig-1)%SQ*2+1)*cs/(2*SQ), cellY(row) + ((dig-1)/SQ*2+1)*cs/(2*SQ)
+	}
+
+	// highlights under the lines and digits
+	if e := opts.Step; e != nil {
+		for _, c := range e.Cells {
+			d.rects = append(d.rects, rect{cellX(c.Col), cellY(c.Row), cs, cs, colorPattern})
+		}
+		for _, p := range e.Placements {
+			d.rects = append(d.rects, rect{cellX(p.Col), cellY(p.Row), cs, cs, colorPlacement})
+		}
+		for _, el := range e.Elims {
+			x, y := candXY(el.Row, el.Col, el.Dig)
+			r := cs / (2 * SQ)
+			d.rects = append(d.rects, rect{x - r, y - r, 2 * r, 2 * r, colorElim})
+		}
+	}
+
+	// thin lines between cells, thick ones between boxes or jigsaw regions. Thick lines
+	// run on over the corners where they meet.
+	thick := maxInt(2, cs/20)
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			if j < N-1 {
+				if BlkOf(i, j) != BlkOf(i, j+1) {
+					d.rects = append(d.rects, rect{cellX(j+1) - thick/2, cellY(i) - thick/2, thick, cs + thick, colorLine})
+				} else {
+					d.rects = append(d.rects, rect{cellX(j + 1), cellY(i), 1, cs, colorThin})
+				}
+			}
+			if i < N-1 {
+				if BlkOf(i, j) != BlkOf(i+1, j) {
+					d.rects = append(d.rects, rect{cellX(j) - thick/2, cellY(i+1) - thick/2, cs + thick, thick, colorLine})
+				} else {
+					d.rects = append(d.rects, rect{cellX(j), cellY(i + 1), cs, 1, colorThin})
+				}
+			}
+		}
+	}
+	d.rects = append(d.rects,
+		rect{margin - thick/2, margin - thick/2, N*cs + thick, thick, colorLine},
+		rect{margin - thick/2, margin + N*cs - thick/2, N*cs + thick, thick, colorLine},
+		rect{margin - thick/2, margin - thick/2, thick, N*cs + thick, colorLine},
+		rect{margin + N*cs - thick/2, margin - thick/2, thick, N*cs + thick, colorLine})
+
+	// digits and candidates
+	elim := map[Elim]bool{}
+	if opts.Step != nil {
+		for _, el := range opts.Step.Elims {
+			elim[el] = true
+		}
+	}
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			if dig := m[i][j]; dig != 0 {
+				c := colorGiven
+				if opts.Givens != nil && opts.Givens[i][j] == 0 {
+					c = colorSolved
+				}
+				d.texts = append(d.texts, text{cellX(j) + cs/2, cellY(i) + cs/2, cs * 3 / 5, dig, c})
+				continue
+			}
+			if opts.Cands == nil {
+				continue
+			}
+			for _, dig := range opts.Cands[i][j] {
+				x, y := candXY(i, j, dig)
+				c := colorCand
+				if elim[Elim{Row: i, Col: j, Dig: dig}] {
+					c = colorElimText
+				}
+				d.texts = append(d.texts, text{x, y, cs / 4, dig, c})
+			}
+		}
+	}
+	return d
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Returns an error unless the extension of the file is one WriteFile can write
+func CheckFile(fname string) error {
+	switch strings.ToLower(filepath.Ext(fname)) {
+	case ".png", ".svg":
+		return nil
+	}
+	return fmt.Errorf("%s: expected a .svg or .png file", fname)
+}
+
+// Write the drawing of the grid to the file, as PNG or SVG by the extension of the file
+func WriteFile(fname string, m Intmat, opts Options) error {
+	if err := CheckFile(fname); err != nil {
+		return err
+	}
+	write := SVG
+	if strings.ToLower(filepath.Ext(fname)) == ".png" {
+		write = PNG
+	}
+
+	f, err := os.Create(fname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := write(f, m, opts); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// Write the drawing as SVG
+func SVG(w io.Writer, m Intmat, opts Options) error {
+	d := layout(m, opts)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n",
+		d.width, d.height, d.width, d.height)
+	for _, r := range d.rects {
+		fmt.Fprintf(&b, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", r.x, r.y, r.w, r.h, hex(r.c))
+	}
+	for _, t := range d.texts {
+		fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\" font-family=\"sans-serif\" font-size=\"%d\" fill=\"%s\" text-anchor=\"middle\" dominant-baseline=\"central\">%d</text>\n",
+			t.x, t.y, t.size, hex(t.c), t.dig)
+	}
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
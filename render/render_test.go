@@ -0,0 +1,96 @@
+package render
+
+import (
+	"bytes"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/mjwong/sudoku2/lib"
+	. "github.com/mjwong/sudoku2/linkedlist"
+)
+
+const puzzle1 = "...15....91..764..5.6.4.3........69.6..5.4..7.71........7.3.9.6..386..15....95..."
+
+// testXW1.txt of the main package
+const testXW1 = ".4...8..37..4.38...3..16..49.4.6.38..6..3.49..23.4...64..12..3.31268..45...3.4.1."
+
+// The X-wing on 2 the rules take for testXW1.txt after its first naked pair, in rows 1
+// and 3, cols 1 and 4. TestNextXWing of the main package checks it is the step found.
+var xwing = Event{
+	Technique: TechXWing,
+	Digits:    []int{2},
+	Cells:     []Coord{{Row: 0, Col: 0}, {Row: 2, Col: 0}, {Row: 0, Col: 3}, {Row: 2, Col: 3}},
+	Elims:     []Elim{{Row: 0, Col: 6, Dig: 2}, {Row: 0, Col: 7, Dig: 2}, {Row: 2, Col: 6, Dig: 2}, {Row: 2, Col: 7, Dig: 2}},
+}
+
+func TestSVG(t *testing.T) {
+	m := PopulateMat(testXW1)
+	_, pm := GetPossibleMat(m)
+
+	var b bytes.Buffer
+	if err := SVG(&b, m, Options{CellSize: 40, Cands: &pm, Step: &xwing}); err != nil {
+		t.Fatal(err)
+	}
+	s := b.String()
+
+	if !strings.HasPrefix(s, `<svg xmlns="http://www.w3.org/2000/svg" width="380" height="380"`) {
+		t.Fatalf("Expected an svg of 380 pixels but got %.80s.\n", s)
+	}
+	if n := strings.Count(s, `fill="`+hex(colorPattern)+`"`); n != len(xwing.Cells) {
+		t.Fatalf("Expected %d pattern cells but got %d.\n", len(xwing.Cells), n)
+	}
+	if !strings.Contains(s, `fill="`+hex(colorElim)+`"`) {
+		t.Fatal("Expected the elimination to be highlighted.")
+	}
+	if n := strings.Count(s, `fill="`+hex(colorGiven)+`" text-anchor`); n != N*N-CountEmpty(m) {
+		t.Fatalf("Expected %d clues but got %d.\n", N*N-CountEmpty(m), n)
+	}
+}
+
+func TestPNG(t *testing.T) {
+	m := PopulateMat(testXW1)
+
+	var b bytes.Buffer
+	if err := PNG(&b, m, Options{CellSize: 30, Step: &xwing}); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := img.Bounds(); r.Dx() != N*30+2*margin || r.Dy() != N*30+2*margin {
+		t.Fatalf("Expected %d pixels square but got %v.\n", N*30+2*margin, r)
+	}
+
+	// corner of the pattern cell r3c4, clear of its lines and digit
+	r, g, bl, _ := img.At(margin+3*30+4, margin+2*30+4).RGBA()
+	if uint8(r>>8) != colorPattern.R || uint8(g>>8) != colorPattern.G || uint8(bl>>8) != colorPattern.B {
+		t.Fatalf("Expected the pattern colour but got %d %d %d.\n", r>>8, g>>8, bl>>8)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	m := PopulateMat(puzzle1)
+	dir := t.TempDir()
+
+	for _, name := range []string{"grid.svg", "grid.png"} {
+		if err := WriteFile(filepath.Join(dir, name), m, Options{}); err != nil {
+			t.Fatal(err)
+		}
+		if fi, err := os.Stat(filepath.Join(dir, name)); err != nil || fi.Size() == 0 {
+			t.Fatalf("Expected %s to be written.\n", name)
+		}
+	}
+	if err := WriteFile(filepath.Join(dir, "grid.pdf"), m, Options{}); err == nil {
+		t.Fatal("Expected an error for a .pdf file.")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "grid.pdf")); !os.IsNotExist(err) {
+		t.Fatal("Expected no .pdf file to be left behind.")
+	}
+	if CheckFile("grid.PNG") != nil || CheckFile("grid.jpg") == nil {
+		t.Fatal("Expected .PNG to be accepted and .jpg refused.")
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/mjwong/sudoku2/lib"
+	. "github.com/mjwong/sudoku2/matchlist"
+)
+
+// Document of the -format json output, one per puzzle
+type jsonReport struct {
+	Line       int             `json:"line,omitempty"` // line of the puzzle in the -i file
+	Input      string          `json:"input"`
+	Grid       string          `json:"grid"`
+	Solved     bool            `json:"solved"`
+	Empty      int             `json:"empty"`
+	Rule       int             `json:"rule"`       // rules chosen by -r
+	RuleCounts map[int]int     `json:"ruleCounts"` // digits or patterns found per rule
+	RuleTimes  map[int]float64 `json:"ruleTimesMs"`
+	Steps      []jsonStep      `json:"steps"`
+	Iterations int             `json:"iterations"` // of iterMat
+	Elapsed    float64         `json:"elapsedMs"`
+
+	ruleTimes map[int]time.Duration
+}
+
+// Step of a rule from its Matchlist
+type jsonStep struct {
+	Rule  int        `json:"rule"`
+	Desc  string     `json:"desc"`
+	Cells []jsonCell `json:"cells"`
+}
+
+type jsonCell struct {
+	Row  int    `json:"row"`
+	Col  int    `json:"col"`
+	Name string `json:"name"` // e.g. r3c5
+	Vals []int  `json:"vals"`
+}
+
+// Report of the puzzle being solved if the output is json, otherwise nil
+var report *jsonReport
+
+func newReport(m Intmat) *jsonReport {
+	return &jsonReport{
+		Input:      MatToString(m),
+		Rule:       *rule,
+		RuleCounts: map[int]int{},
+		RuleTimes:  map[int]float64{},
+		Steps:      []jsonStep{},
+		ruleTimes:  map[int]time.Duration{},
+	}
+}
+
+// Number of a rule from its func name, e.g. 3 for main.rule3
+func ruleNumber(fn fnRule) int {
+	name := funcName(GetFunctionName(fn))
+	n, _ := strconv.Atoi(strings.TrimPrefix(name[strings.LastIndex(name, ".")+1:], "rule"))
+	return n
+}
+
+// Print the matches of a rule and add them to the report
+func printResult(n int, matched *Matchlist, desc string) {
+	matched.PrintResult(desc)
+
+	if report == nil {
+		return
+	}
+	for _, arr := range matched.Matches() {
+		step := jsonStep{Rule: n, Desc: desc}
+		for _, c := range arr {
+			step.Cells = append(step.Cells, jsonCell{Row: c.Row, Col: c.Col, Name: CellName(c.Row, c.Col), Vals: c.Vals})
+		}
+		report.Steps = append(report.Steps, step)
+	}
+}
+
+// Add the count and the time of a run of a rule to the report
+func reportRule(n, cnt int, elapsed time.Duration) {
+	if report != nil {
+		report.RuleCounts[n] += cnt
+		report.ruleTimes[n] += elapsed
+	}
+}
+
+// Finish the report with the final grid and the iterations of iterMat, and print it on
+// a line
+func (r *jsonReport) print(final Intmat, iterations int, elapsed time.Duration) {
+	r.Grid = MatToString(final)
+	r.Empty = CountEmpty(final)
+	r.Iterations = iterations
+	r.Elapsed = millis(elapsed)
+	for n, d := range r.ruleTimes {
+		r.RuleTimes[n] = millis(d)
+	}
+
+	restore := quiet()
+	r.Solved = r.Empty == 0 && CheckSums(final)
+	restore()
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(b))
+}
+
+// Duration in ms to the nearest us
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
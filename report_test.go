@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+func TestJSONReport(t *testing.T) {
+	s := newSolver()
+
+	input, err := readPuzzle("difficult1.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := PopulateMat(input)
+
+	s.PrepPmat(input)
+	report = newReport(m)
+	defer func() { report = nil }()
+
+	restore := quiet()
+	s.RuleLoop(s.rule3, RuleTable[3], Zero)
+	s.RuleLoop(s.rule1, RuleTable[1], Zero)
+	restore()
+
+	if ruleNumber(s.rule3) != 3 || ruleNumber(s.rule20) != 20 {
+		t.Fatalf("Expected rules 3 and 20 but got %d and %d.\n", ruleNumber(s.rule3), ruleNumber(s.rule20))
+	}
+	if len(report.Steps) != report.RuleCounts[1]+report.RuleCounts[3] {
+		t.Fatalf("Expected %d steps but got %d.\n", report.RuleCounts[1]+report.RuleCounts[3], len(report.Steps))
+	}
+
+	// print to a pipe and read the document back
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	report.print(s.mat, 0, time.Millisecond)
+	os.Stdout = stdout
+	w.Close()
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc jsonReport
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatalf("%v: %s", err, b)
+	}
+
+	sol, _ := SolveUnique(m)
+	if doc.Input != input || doc.Grid != MatToString(sol) || !doc.Solved || doc.Empty != 0 || doc.Elapsed != 1 {
+		t.Fatalf("Expected %s solved but got %+v.\n", input, doc)
+	}
+	for _, step := range doc.Steps {
+		c := step.Cells[0]
+		if len(step.Cells) != 1 || sol[c.Row][c.Col] != c.Vals[0] || c.Name != CellName(c.Row, c.Col) {
+			t.Fatalf("Expected a digit of the solution but got %+v.\n", step)
+		}
+	}
+}
@@ -10,7 +10,7 @@ import (
 	"gopkg.in/gookit/color.v1"
 )
 
-func rule1() (*Matchlist, int, time.Duration) {
+func (s *solver) rule1() (*Matchlist, int, time.Duration) {
 	var (
 		col, row                     int // position of last empty cell
 		digit, count                 int
@@ -21,7 +21,7 @@ func rule1() (*Matchlist, int, time.Duration) {
 	start = time.Now()
 	matched = &Matchlist{}
 
-	currNode := emptyL.Head
+	currNode := s.emptyL.Head
 	if currNode == nil {
 		color.Yellow.Println("Rule 1: Empty list.")
 	} else {
@@ -35,18 +35,16 @@ func rule1() (*Matchlist, int, time.Duration) {
 			if len(currNode.Vals) == 1 {
 				digit = currNode.Vals[0]
 				matched.AddCell(currNode, digit)
-				emptyL.DelNode(currNode) // remove current Node from possibility list
-				mat[row][col] = digit
-				mat2[row][col] = nil
-				emptyCnt--
+				s.beginEvent(Event{Technique: TechNakedSingle})
+				s.placeDigit(currNode, digit)
 				count++
 
 				// check that there is no occurrence in same row, col or block
-				notInRow = !FindDigitInRow(DebugFn(2), mat2, row, col, digit)
-				notInCol = !FindDigitInCol(DebugFn(2), mat2, row, col, digit)
-				notInBlk = !FindDigitInBlk(DebugFn(2), mat2, row, col, digit)
+				notInRow = !FindDigitInRow(DebugFn(2), s.mat2, row, col, digit)
+				notInCol = !FindDigitInCol(DebugFn(2), s.mat2, row, col, digit)
+				notInBlk = !FindDigitInBlk(DebugFn(2), s.mat2, row, col, digit)
 				// If found, erase any occurrence of the digit in the same row, col or block
-				findAndEraseDigit(row, col, digit, notInRow, notInCol, notInBlk)
+				s.findAndEraseDigit(row, col, digit, notInRow, notInCol, notInBlk)
 			}
 			currNode = currNode.Next
 		}
@@ -56,7 +54,7 @@ func rule1() (*Matchlist, int, time.Duration) {
 
 // Rule 1a	Open singles
 //          Search the specified row or column for open singles
-func rule1a(row, col int) (*Matchlist, int, time.Duration) {
+func (s *solver) rule1a(row, col int) (*Matchlist, int, time.Duration) {
 	var (
 		digit, count                 int
 		notInRow, notInCol, notInBlk bool
@@ -73,65 +71,59 @@ func rule1a(row, col int) (*Matchlist, int, time.Duration) {
 
 	if row >= 0 && col < 0 { // skip row checking if negative value
 		for c := 0; c < N; c++ {
-			if len(mat2[row][c]) == 1 {
-				digit = mat2[row][c][0]
-				node = emptyL.GetNodeFoRCell(row, c)
+			if len(s.mat2[row][c]) == 1 {
+				digit = s.mat2[row][c][0]
+				node = s.emptyL.GetNodeFoRCell(row, c)
 				matched.AddCell(node, digit)
-				emptyL.DelNode(node) // remove current Node from possibility list
-				mat[row][c] = digit
-				mat2[row][c] = nil
-				emptyCnt--
+				s.beginEvent(Event{Technique: TechNakedSingle})
+				s.placeDigit(node, digit)
 				count++
 
 				// check that there is no occurrence in same row, col or block
-				notInRow = !FindDigitInRow(DebugFn(2), mat2, row, c, digit)
-				notInCol = !FindDigitInCol(DebugFn(2), mat2, row, c, digit)
-				notInBlk = !FindDigitInBlk(DebugFn(2), mat2, row, c, digit)
+				notInRow = !FindDigitInRow(DebugFn(2), s.mat2, row, c, digit)
+				notInCol = !FindDigitInCol(DebugFn(2), s.mat2, row, c, digit)
+				notInBlk = !FindDigitInBlk(DebugFn(2), s.mat2, row, c, digit)
 				// If found, erase any occurrence of the digit in the same row, col or block
-				findAndEraseDigit(row, c, digit, notInRow, notInCol, notInBlk)
+				s.findAndEraseDigit(row, c, digit, notInRow, notInCol, notInBlk)
 			}
 		}
 	}
 
 	if col >= 0 && row < 0 { // skip col checking if negative value
 		for r := 0; r < N; r++ {
-			if len(mat2[r][col]) == 1 {
-				digit = mat2[r][col][0]
-				node = emptyL.GetNodeFoRCell(r, col)
+			if len(s.mat2[r][col]) == 1 {
+				digit = s.mat2[r][col][0]
+				node = s.emptyL.GetNodeFoRCell(r, col)
 				matched.AddCell(node, digit)
-				emptyL.DelNode(node) // remove current Node from possibility list
-				mat[r][col] = digit
-				mat2[r][col] = nil
-				emptyCnt--
+				s.beginEvent(Event{Technique: TechNakedSingle})
+				s.placeDigit(node, digit)
 				count++
 
 				// check that there is no occurrence in same row, col or block
-				notInRow = !FindDigitInRow(DebugFn(2), mat2, r, col, digit)
-				notInCol = !FindDigitInCol(DebugFn(2), mat2, r, col, digit)
-				notInBlk = !FindDigitInBlk(DebugFn(2), mat2, r, col, digit)
+				notInRow = !FindDigitInRow(DebugFn(2), s.mat2, r, col, digit)
+				notInCol = !FindDigitInCol(DebugFn(2), s.mat2, r, col, digit)
+				notInBlk = !FindDigitInBlk(DebugFn(2), s.mat2, r, col, digit)
 				// If found, erase any occurrence of the digit in the same row, col or block
-				findAndEraseDigit(r, col, digit, notInRow, notInCol, notInBlk)
+				s.findAndEraseDigit(r, col, digit, notInRow, notInCol, notInBlk)
 			}
 		}
 	}
 
 	if row >= 0 && col >= 0 { // check only this cell
-		if len(mat2[row][col]) == 1 {
-			digit = mat2[row][col][0]
-			node = emptyL.GetNodeFoRCell(row, col)
+		if len(s.mat2[row][col]) == 1 {
+			digit = s.mat2[row][col][0]
+			node = s.emptyL.GetNodeFoRCell(row, col)
 			matched.AddCell(node, digit)
-			emptyL.DelNode(node) // remove current Node from possibility list
-			mat[row][col] = digit
-			mat2[row][col] = nil
-			emptyCnt--
+			s.beginEvent(Event{Technique: TechNakedSingle})
+			s.placeDigit(node, digit)
 			count++
 
 			// check that there is no occurrence in same row, col or block
-			notInRow = !FindDigitInRow(DebugFn(2), mat2, row, col, digit)
-			notInCol = !FindDigitInCol(DebugFn(2), mat2, row, col, digit)
-			notInBlk = !FindDigitInBlk(DebugFn(2), mat2, row, col, digit)
+			notInRow = !FindDigitInRow(DebugFn(2), s.mat2, row, col, digit)
+			notInCol = !FindDigitInCol(DebugFn(2), s.mat2, row, col, digit)
+			notInBlk = !FindDigitInBlk(DebugFn(2), s.mat2, row, col, digit)
 			// If found, erase any occurrence of the digit in the same row, col or block
-			findAndEraseDigit(row, col, digit, notInRow, notInCol, notInBlk)
+			s.findAndEraseDigit(row, col, digit, notInRow, notInCol, notInBlk)
 		}
 	}
 
@@ -192,8 +184,8 @@ func rule1() (*Matchlist, int) {
 						color.LightBlue.Printf("Digit %d of cell [%d][%d] not in col %d\n", digit, row, col, col)
 					}
 					if notInBlk {
-						color.LightBlue.Printf("Digit %d of cell [%d][%d] not in blk [%d][%d]\n",
-							digit, row, col, row/SQ, col/SQ)
+						color.LightBlue.Printf("Digit %d of cell [%d][%d] not in blk %d\n",
+							digit, row, col, BlkOf(row, col))
 					}
 				}
 
@@ -216,7 +208,7 @@ func rule1() (*Matchlist, int) {
 					eraseDigitFromBlk(row, col, digit)
 
 					if *debugPtr {
-						color.LightBlue.Printf("After deletion from blk [%d,%d]: %v\n", row/SQ, col/SQ, getBlkOfPossibleMat(mat2, row, col))
+						color.LightBlue.Printf("After deletion from blk %d: %v\n", BlkOf(row, col), getBlkOfPossibleMat(mat2, row, col))
 					}
 				}
 				if notInRow && notInCol && notInBlk {
@@ -8,16 +8,17 @@ import (
 )
 
 func TestRule1(t *testing.T) {
+	s := newSolver()
 
 	input := ".341528699.837645252.948371245.136986895.413737168.524857231.464938672.516249578."
-	PrepPmat(input)
+	s.PrepPmat(input)
 
-	matched, cnt, _ := rule1()
+	matched, cnt, _ := s.rule1()
 	if cnt != 9 {
 		t.Fatalf("Expected 8 but got %d\n", cnt)
 	}
 
-	if !CheckSums(mat) {
+	if !CheckSums(s.mat) {
 		t.Fatalf("There are errors in the resulting matrix.\n")
 	}
 
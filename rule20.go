@@ -13,7 +13,7 @@ import (
 // Rectangular box  pattern. If the same no. appears in the corner cells of a rectangular box,
 // then that no. can be safely eliminated (crossed out) in all columns and rows that intersect
 // with the corner cells of the rectangular box.
-func rule20() (*Matchlist, int, time.Duration) {
+func (s *solver) rule20() (*Matchlist, int, time.Duration) {
 	var (
 		count      int
 		foundXWing bool
@@ -29,11 +29,11 @@ func rule20() (*Matchlist, int, time.Duration) {
 	foundXWing = true
 	debug = DebugFn(2)
 
-	fmt.Printf("Func: %s. Debug: %t\n", FuncName(1), debug)
+	fmt.Printf("Func: %s. Debug: %t\n", funcName(FuncName(1)), debug)
 
 	for dig := 1; dig <= N; dig++ {
 		foundXWing = false
-		currNode := emptyL.Head
+		currNode := s.emptyL.Head
 
 		if currNode == nil {
 			color.Yellow.Println("Rule 20: Empty list.")
@@ -42,30 +42,27 @@ func rule20() (*Matchlist, int, time.Duration) {
 			for currNode != nil {
 				// Check block contains only 2 possible digit in exactly 2 places
 				// This digit may be hidden in the list of possibile digits.
-				for bi := 0; bi < SQ; bi++ {
+				for b := 0; b < N; b++ {
+					arrC, inBlk = checkBlkForDigit(s.mat2, b, dig, 2)
+					if inBlk { // exactly 2 same digits in this block
 
-					for bj := 0; bj < SQ; bj++ {
-						arrC, inBlk = checkBlkForDigit(mat2, bi, bj, dig, 2)
-						if inBlk { // exactly 2 same digits in this block
+						// Are they in the same row?
+						matched, foundXWing = s.checkSameRowXwing(debug, b, dig, arrC, inBlk, matched)
+						if foundXWing {
+							count++
 
-							// Are they in the same row?
-							matched, foundXWing = checkSameRowXwing(debug, bi, bj, dig, arrC, inBlk, matched)
-							if foundXWing {
-								count++
-
-								if debug {
-									fmt.Println("Found x-wing in same row.")
-								}
+							if debug {
+								fmt.Println("Found x-wing in same row.")
 							}
+						}
 
-							// Are they in the same column?
-							matched, foundXWing = checkSameColXwing(debug, bi, bj, dig, arrC, inBlk, matched)
-							if foundXWing {
-								count++
+						// Are they in the same column?
+						matched, foundXWing = s.checkSameColXwing(debug, b, dig, arrC, inBlk, matched)
+						if foundXWing {
+							count++
 
-								if debug {
-									fmt.Println("Found x-wing in same col.")
-								}
+							if debug {
+								fmt.Println("Found x-wing in same col.")
 							}
 						}
 					}
@@ -77,8 +74,9 @@ func rule20() (*Matchlist, int, time.Duration) {
 	return matched, count, time.Since(start)
 }
 
-func checkSameRowXwing(debug bool, bi, bj, dig int, arrC []Coord, inBlk bool, foundList *Matchlist) (*Matchlist, bool) {
-	blkListi := []int{}
+// The 2 cells of the first block are in the same row. Search the other blocks for a
+// second pair of cells in the same columns. Blocks need not be squares (jigsaw regions).
+func (s *solver) checkSameRowXwing(debug bool, b, dig int, arrC []Coord, inBlk bool, foundList *Matchlist) (*Matchlist, bool) {
 	foundXWing := false
 
 	if arrC[0].Row == arrC[1].Row {
@@ -86,13 +84,13 @@ func checkSameRowXwing(debug bool, bi, bj, dig int, arrC []Coord, inBlk bool, fo
 			color.LightBlue.Printf("The 2 same digits no. %d are both in row %d.\n",
 				dig, arrC[0].Row)
 		}
-		// check blks vertically, i.e. this col of blocks
-		for bi2 := 0; bi2 < SQ; bi2++ {
-			if bi != bi2 { // not the original block
-				arrC2, inBlk2 := checkBlkForDigit(mat2, bi2, bj, dig, 2)
+		// check the other blks. For square blocks, only this col of blocks can match.
+		for b2 := 0; b2 < N; b2++ {
+			if b != b2 { // not the original block
+				arrC2, inBlk2 := checkBlkForDigit(s.mat2, b2, dig, 2)
 				if inBlk2 { // found exactly 2 same digits in second block
 					if debug {
-						color.LightBlue.Printf("Found 2nd block [%d,%d].\n", bi2, bj)
+						color.LightBlue.Printf("Found 2nd block %d.\n", b2)
 					}
 
 					// Locations of the X-wing cells in first block
@@ -119,10 +117,9 @@ func checkSameRowXwing(debug bool, bi, bj, dig int, arrC []Coord, inBlk bool, fo
 						if debug {
 							color.LightMagenta.Printf("Found X-wing #%d: [%d,%d], [%d,%d], [%d,%d], [%d,%d].\n",
 								dig, rowXw1, colXw1, rowXw2, colXw2, rowXw3, colXw3, rowXw4, colXw4)
-							PrintPossibleMat(mat2)
+							PrintPossibleMat(s.mat2)
 						}
 
-						blkListi = append(blkListi, bi2)
 						colList := []int{}
 						colList = append(colList, colXw1)
 						colList = append(colList, colXw2)
@@ -130,13 +127,20 @@ func checkSameRowXwing(debug bool, bi, bj, dig int, arrC []Coord, inBlk bool, fo
 							color.LightYellow.Printf("Collist: %v\n", colList)
 						}
 
+						s.beginEvent(Event{
+							Technique: TechXWing,
+							Digits:    []int{dig},
+							Cells: []Coord{{Row: rowXw1, Col: colXw1}, {Row: rowXw2, Col: colXw2},
+								{Row: rowXw3, Col: colXw3}, {Row: rowXw4, Col: colXw4}},
+						})
+
 						// find and delete any occurrences of this digit elsewhere in the cols of colList
 						// excluding the row positions of the 2 cells forming the X-wing.
 						for _, c := range colList {
 							for r := 0; r < N; r++ {
 								if r != rowXw1 && r != rowXw2 && r != rowXw3 && r != rowXw4 {
 									// erase digit from this cell
-									eraCnt, erased := eraseDigitFromColMulti(c, dig, []int{rowXw1, rowXw2, rowXw3, rowXw4})
+									eraCnt, erased := s.eraseDigitFromColMulti(c, dig, []int{rowXw1, rowXw2, rowXw3, rowXw4})
 									if erased {
 										foundXWing = true
 
@@ -153,68 +157,15 @@ func checkSameRowXwing(debug bool, bi, bj, dig int, arrC []Coord, inBlk bool, fo
 									if debug {
 										if erased {
 											color.LightMagenta.Printf("X-wing: Erased %d counts of digit %d from col %d.\n", eraCnt, dig, c)
-											PrintPossibleMat(mat2)
+											PrintPossibleMat(s.mat2)
 										}
 									}
 								}
 							}
 						}
 
-						thirdCol := 0 // find the 3rd column
-						for c := 0; c < SQ; c++ {
-							if !Contains(colList, c) {
-								thirdCol = c
-
-								if debug {
-									color.LightYellow.Printf("Found third col no. %d of digit %d.\n", thirdCol, dig)
-								}
-								break
-							}
-						}
-
-						if debug {
-							color.LightYellow.Printf("Row blklist: %v\n", blkListi)
-						}
-
-						for i := 0; i < SQ; i++ { // find third block
-							if !Contains(blkListi, i) {
-
-								if debug {
-									color.LightYellow.Printf("Third blk: %d\n", i)
-								}
-
-								// check whether there is open or hidden single in the
-								// third missing row of this third block
-
-								startRow := i * SQ
-								for r := startRow; r < startRow+SQ; r++ {
-									if len(mat2[r][thirdCol]) == 1 {
-										// insurance check for entire row. Rightfully, we can just check
-										// the entire row since we know the digit can only appear in the
-										// third missing row of the third block, because the first 2 blocks
-										// already contain a pair of the digits each, forming the X-wing.
-										matched, cnt, _ := rule1a(r, thirdCol)
-
-										if debug && cnt > 0 {
-											color.LightYellow.Printf("Rule1a: Found %d counts of open single %d at [%d,%d]\n",
-												cnt, dig, r, thirdCol)
-											matched.PrintResult(RuleTable[20])
-										}
-									}
-
-									// check for hidden singles at this Cell position
-									if mat2[r][thirdCol] != nil {
-										matched3, cnt3, _ := rule3a(r, thirdCol, dig)
-										if debug && cnt3 > 0 {
-											color.LightYellow.Printf("Rule3a: Found %d counts of hidden single %d at [%d,%d]\n",
-												cnt3, dig, r, thirdCol)
-											PrintPossibleMat(mat2)
-											matched3.PrintResult(RuleTable[20])
-										}
-									}
-								}
-							}
-						} // end of find third block
+						// the digit may now be left in one place in the other blocks of the cols
+						s.xwingSingles(debug, dig, b, b2, colList, true)
 					}
 				}
 			}
@@ -223,23 +174,23 @@ func checkSameRowXwing(debug bool, bi, bj, dig int, arrC []Coord, inBlk bool, fo
 	return foundList, foundXWing
 }
 
-func checkSameColXwing(debug bool, bi, bj, dig int, arrC []Coord, inBlk bool, foundList *Matchlist) (*Matchlist, bool) {
-	blkListj := []int{} // col blocks
+// The 2 cells of the first block are in the same col. Search the other blocks for a
+// second pair of cells in the same rows. Blocks need not be squares (jigsaw regions).
+func (s *solver) checkSameColXwing(debug bool, b, dig int, arrC []Coord, inBlk bool, foundList *Matchlist) (*Matchlist, bool) {
 	foundXWing := false
 
 	if arrC[0].Col == arrC[1].Col {
-		blkListj = append(blkListj, bj)
 		if debug {
 			color.LightBlue.Printf("The 2 same digits no. %d are both in col %d.\n",
 				dig, arrC[0].Col)
 		}
-		// check blks horizontally, i.e. this row of blocks
-		for bj2 := 0; bj2 < SQ; bj2++ {
-			if bj != bj2 { // not the original block
-				arrC2, inBlk2 := checkBlkForDigit(mat2, bi, bj2, dig, 2)
+		// check the other blks. For square blocks, only this row of blocks can match.
+		for b2 := 0; b2 < N; b2++ {
+			if b != b2 { // not the original block
+				arrC2, inBlk2 := checkBlkForDigit(s.mat2, b2, dig, 2)
 				if inBlk2 { // found exactly 2 same digits in second block
 					if debug {
-						color.LightBlue.Printf("Found 2nd block [%d,%d].\n", bi, bj2)
+						color.LightBlue.Printf("Found 2nd block %d.\n", b2)
 					}
 
 					// Locations of the X-wing cells in first block
@@ -267,9 +218,8 @@ func checkSameColXwing(debug bool, bi, bj, dig int, arrC []Coord, inBlk bool, fo
 							if debug {
 								color.LightMagenta.Printf("Found X-wing #%d: [%d,%d], [%d,%d], [%d,%d], [%d,%d].\n",
 									dig, rowXw1, colXw1, rowXw2, colXw2, rowXw3, colXw3, rowXw4, colXw4)
-								PrintPossibleMat(mat2)
+								PrintPossibleMat(s.mat2)
 							}
-							blkListj = append(blkListj, bj2)
 							rowList := []int{}
 							rowList = append(rowList, rowXw1)
 							rowList = append(rowList, rowXw2)
@@ -278,13 +228,20 @@ func checkSameColXwing(debug bool, bi, bj, dig int, arrC []Coord, inBlk bool, fo
 								color.LightYellow.Printf("Rowlist: %v\n", rowList)
 							}
 
+							s.beginEvent(Event{
+								Technique: TechXWing,
+								Digits:    []int{dig},
+								Cells: []Coord{{Row: rowXw1, Col: colXw1}, {Row: rowXw2, Col: colXw2},
+									{Row: rowXw3, Col: colXw3}, {Row: rowXw4, Col: colXw4}},
+							})
+
 							// find and delete any occurrences of this digit elsewhere in the rows of rowList
 							// excluding the column positions of the 2 cells forming the X-wing.
 							for _, r := range rowList {
 								for c := 0; c < N; c++ {
 									if c != colXw1 && c != colXw2 && c != colXw3 && c != colXw4 {
 										// erase digit from this cell
-										eraCnt, erased := eraseDigitsFromRowMulti(r, []int{dig}, []int{colXw1, colXw2, colXw3, colXw4})
+										eraCnt, erased := s.eraseDigitsFromRowMulti(r, []int{dig}, []int{colXw1, colXw2, colXw3, colXw4})
 										if erased {
 											foundXWing = true
 
@@ -301,93 +258,83 @@ func checkSameColXwing(debug bool, bi, bj, dig int, arrC []Coord, inBlk bool, fo
 										if debug {
 											if erased {
 												color.LightYellow.Printf("X-wing: Erased %d counts of digit %d from row %d.\n", eraCnt, dig, r)
-												PrintPossibleMat(mat2)
+												PrintPossibleMat(s.mat2)
 											}
 										}
 									}
 								}
 							}
 
-							thirdRow := 0 // find the 3rd row
-							for r := 0; r < SQ; r++ {
-								if !Contains(rowList, r) {
-									thirdRow = r
-
-									if debug {
-										color.LightYellow.Printf("Found third row no. %d of digit %d.\n", thirdRow, dig)
-									}
-									break
-								}
-							}
-
-							if debug {
-								color.LightYellow.Printf("Col blklist: %v\n", blkListj)
-							}
+							// the digit may now be left in one place in the other blocks of the rows
+							s.xwingSingles(debug, dig, b, b2, rowList, false)
+						}
+					}
+				}
+			}
+		}
+	} // end of same col
+	return foundList, foundXWing
+}
 
-							for j := 0; j < SQ; j++ { // find third block horizontally
-								if !Contains(blkListj, j) {
+// Look for open and hidden singles of the digit in the blocks crossed by the lines of
+// an X-wing, other than the blocks b and b2 of the X-wing, in their cells off the lines.
+// The lines are cols if inCols, otherwise rows.
+func (s *solver) xwingSingles(debug bool, dig, b, b2 int, lines []int, inCols bool) {
+	seen := map[int]bool{b: true, b2: true}
+
+	for _, line := range lines {
+		for k := 0; k < N; k++ {
+			blk := BlkOf(k, line)
+			if !inCols {
+				blk = BlkOf(line, k)
+			}
+			if seen[blk] {
+				continue
+			}
+			seen[blk] = true
 
-									if debug {
-										color.LightYellow.Printf("Third blk: %d\n", j)
-									}
+			if debug {
+				color.LightYellow.Printf("Third blk: %d\n", blk)
+			}
+			for _, c := range BlkCells(blk) {
+				if (inCols && Contains(lines, c.Col)) || (!inCols && Contains(lines, c.Row)) {
+					continue
+				}
+				if len(s.mat2[c.Row][c.Col]) == 1 {
+					matched, cnt, _ := s.rule1a(c.Row, c.Col)
 
-									// check whether there is open or hidden single in the
-									// third missing row of this third block
-
-									startCol := j * SQ
-									for c := startCol; c < startCol+SQ; c++ {
-										if len(mat2[thirdRow][c]) == 1 {
-											// insurance check for entire row. Rightfully, we can just check
-											// the entire row since we know the digit can only appear in the
-											// third missing row of the third block, because the first 2 blocks
-											// already contain a pair of the digits each, forming the X-wing.
-											matched, cnt, _ := rule1a(thirdRow, c)
-
-											if debug && cnt > 0 {
-												color.LightYellow.Printf("Rule1a: Found %d counts of open single %d at [%d,%d]\n",
-													cnt, dig, thirdRow, c)
-												matched.PrintResult(RuleTable[20])
-											}
-										}
+					if debug && cnt > 0 {
+						color.LightYellow.Printf("Rule1a: Found %d counts of open single %d at [%d,%d]\n",
+							cnt, dig, c.Row, c.Col)
+						matched.PrintResult(RuleTable[20])
+					}
+				}
 
-										// check for hidden singles at this Cell position
-										if mat2[thirdRow][c] != nil {
-											matched3, cnt3, _ := rule3a(thirdRow, c, dig)
+				// check for hidden singles at this Cell position
+				if s.mat2[c.Row][c.Col] != nil {
+					matched3, cnt3, _ := s.rule3a(c.Row, c.Col, dig)
 
-											if debug && cnt3 > 0 {
-												color.LightYellow.Printf("Rule3a: Found %d counts of hidden single %d at [%d,%d]\n",
-													cnt3, dig, thirdRow, c)
-												PrintPossibleMat(mat2)
-												matched3.PrintResult(RuleTable[20])
-											}
-										}
-									}
-								}
-							} // end of find third block horizontally
-						}
+					if debug && cnt3 > 0 {
+						color.LightYellow.Printf("Rule3a: Found %d counts of hidden single %d at [%d,%d]\n",
+							cnt3, dig, c.Row, c.Col)
+						PrintPossibleMat(s.mat2)
+						matched3.PrintResult(RuleTable[20])
 					}
 				}
 			}
 		}
-	} // end of same col
-	return foundList, foundXWing
+	}
 }
 
-func checkBlkForDigit(m Pmat, bx, by, dig, occurence int) ([]Coord, bool) {
-	var (
-		count, startRow, startCol int
-	)
-
-	startRow = bx * SQ
-	startCol = by * SQ
+// Find the cells of block (region) blk that contain the digit
+func checkBlkForDigit(m Pmat, blk, dig, occurence int) ([]Coord, bool) {
+	var count int
 	arr := []Coord{}
 
-	for i := startRow; i < startRow+SQ; i++ {
-		for j := startCol; j < startCol+SQ; j++ {
-			if Contains(m[i][j], dig) {
-				arr = append(arr, Coord{Row: i, Col: j})
-				count++
-			}
+	for _, c := range BlkCells(blk) {
+		if Contains(m[c.Row][c.Col], dig) {
+			arr = append(arr, c)
+			count++
 		}
 	}
 
@@ -431,16 +378,13 @@ func checkColForDigit(m Pmat, col, dig, occurence int) ([]Coord, bool) {
 	return arr, false
 }
 
-func checkDigitInColOfBlk(m Pmat, bx, col, dig, occurence int) ([]Coord, bool) {
-	var (
-		count, startRow int
-	)
-	startRow = bx * SQ
+func checkDigitInColOfBlk(m Pmat, blk, col, dig, occurence int) ([]Coord, bool) {
+	var count int
 	arr := []Coord{}
 
-	for i := startRow; i < startRow+SQ; i++ {
-		if Contains(m[i][col], dig) {
-			arr = append(arr, Coord{Row: i, Col: col})
+	for _, c := range BlkCells(blk) {
+		if c.Col == col && Contains(m[c.Row][c.Col], dig) {
+			arr = append(arr, c)
 			count++
 		}
 	}
@@ -18,38 +18,39 @@ func TestCheckBlkForDigit(t *testing.T) {
 	pm[2][0] = []int{2, 5, 8}
 	pm[2][2] = []int{5, 8, 9}
 
-	arr, inBlk := checkBlkForDigit(pm, 0, 0, 2, 2)
+	arr, inBlk := checkBlkForDigit(pm, 0, 2, 2)
 	if !inBlk {
 		t.Fatalf("Should find 2 same digits in blk but found %d.\n", len(arr))
 	}
 
-	arr, inBlk = checkBlkForDigit(pm, 0, 0, 5, 2)
+	arr, inBlk = checkBlkForDigit(pm, 0, 5, 2)
 	if inBlk {
 		t.Fatalf("Should not find 2 same digits in blk but found %d.\n", len(arr))
 	}
 }
 
 func TestEraseDigitFromRowMulti(t *testing.T) {
+	s := newSolver()
 
-	mat2 = Pmat{}
-	mat2[0][0] = []int{1, 2, 5, 6}
-	mat2[0][2] = []int{1, 5, 6, 9}
-	mat2[0][3] = []int{2, 5, 7, 9}
-	mat2[0][4] = []int{5, 7, 9}
-	mat2[0][6] = []int{1, 2, 5, 6, 7, 9}
-	mat2[0][7] = []int{2, 5, 6, 7}
+	s.mat2 = Pmat{}
+	s.mat2[0][0] = []int{1, 2, 5, 6}
+	s.mat2[0][2] = []int{1, 5, 6, 9}
+	s.mat2[0][3] = []int{2, 5, 7, 9}
+	s.mat2[0][4] = []int{5, 7, 9}
+	s.mat2[0][6] = []int{1, 2, 5, 6, 7, 9}
+	s.mat2[0][7] = []int{2, 5, 6, 7}
 
-	emptyL = CreatelinkedList()
-	emptyL.AddCell(0, 6, []int{1, 2, 5, 6, 7, 9})
-	emptyL.AddCell(0, 7, []int{2, 5, 6, 7})
+	s.emptyL = CreatelinkedList()
+	s.emptyL.AddCell(0, 6, []int{1, 2, 5, 6, 7, 9})
+	s.emptyL.AddCell(0, 7, []int{2, 5, 6, 7})
 
-	PrintPossibleMat(mat2)
+	PrintPossibleMat(s.mat2)
 
-	startCnt := CountElemPosMat(mat2)
+	startCnt := CountElemPosMat(s.mat2)
 
-	eraCnt, erased := eraseDigitsFromRowMulti(0, []int{2}, []int{0, 3})
+	eraCnt, erased := s.eraseDigitsFromRowMulti(0, []int{2}, []int{0, 3})
 
-	endCnt := CountElemPosMat(mat2)
+	endCnt := CountElemPosMat(s.mat2)
 
 	if !erased {
 		t.Fatal("Should be erased but not.\n")
@@ -59,11 +60,11 @@ func TestEraseDigitFromRowMulti(t *testing.T) {
 		t.Fatalf("2 counts of digit 2 should be erased but got %d.\n", eraCnt)
 	}
 
-	if Contains(mat2[0][6], 2) {
+	if Contains(s.mat2[0][6], 2) {
 		t.Fatal("Possibility matrix cell [0,6] should not contain 2.\n")
 	}
 
-	if Contains(mat2[0][7], 2) {
+	if Contains(s.mat2[0][7], 2) {
 		t.Fatal("Possibility matrix cell [0,7] should not contain 2.\n")
 	}
 
@@ -120,19 +121,21 @@ func TestContainsXwing(t *testing.T) {
 }
 
 func TestContainsXwing2(t *testing.T) {
-	mat2 = Pmat{}
-	mat2[0][0] = []int{1, 2, 5, 6}
-	mat2[0][2] = []int{1, 5, 6, 9}
-	mat2[0][3] = []int{2, 5, 7, 9}
-	mat2[0][4] = []int{5, 7, 9}
-	mat2[0][6] = []int{1, 2, 5, 6, 7, 9}
-	mat2[0][7] = []int{2, 5, 6, 7}
-	mat2[2][0] = []int{2, 5, 8}
-	mat2[2][2] = []int{5, 8, 9}
-	mat2[2][3] = []int{2, 5, 7, 9}
-	mat2[2][6] = []int{2, 5, 7, 9}
-	mat2[2][7] = []int{2, 5, 7}
-	PrintPossibleMat(mat2)
+	s := newSolver()
+
+	s.mat2 = Pmat{}
+	s.mat2[0][0] = []int{1, 2, 5, 6}
+	s.mat2[0][2] = []int{1, 5, 6, 9}
+	s.mat2[0][3] = []int{2, 5, 7, 9}
+	s.mat2[0][4] = []int{5, 7, 9}
+	s.mat2[0][6] = []int{1, 2, 5, 6, 7, 9}
+	s.mat2[0][7] = []int{2, 5, 6, 7}
+	s.mat2[2][0] = []int{2, 5, 8}
+	s.mat2[2][2] = []int{5, 8, 9}
+	s.mat2[2][3] = []int{2, 5, 7, 9}
+	s.mat2[2][6] = []int{2, 5, 7, 9}
+	s.mat2[2][7] = []int{2, 5, 7}
+	PrintPossibleMat(s.mat2)
 
 	arr := []RCell{}
 	arr = AddRCellToArr(arr, 0, 0, 2)
@@ -163,50 +166,52 @@ func TestContainsXwing2(t *testing.T) {
 }
 
 func TestRule20(t *testing.T) {
-	mat2 = Pmat{}
-	mat2[0][0] = []int{1, 2, 5, 6}
-	mat2[0][2] = []int{1, 5, 6, 9}
-	mat2[0][3] = []int{2, 5, 7, 9}
-	mat2[0][4] = []int{5, 7, 9}
-	mat2[0][6] = []int{1, 2, 5, 6, 7, 9}
-	mat2[0][7] = []int{2, 5, 6, 7}
-	mat2[2][0] = []int{2, 5, 8}
-	mat2[2][2] = []int{5, 8, 9}
-	mat2[2][3] = []int{2, 5, 7, 9}
-	mat2[2][6] = []int{2, 5, 7, 9}
-	mat2[2][7] = []int{2, 5, 7}
+	s := newSolver()
 
-	emptyL = CreatelinkedList()
-	emptyL.AddCell(0, 6, []int{1, 2, 5, 6, 7, 9})
-	emptyL.AddCell(0, 7, []int{2, 5, 6, 7})
-	emptyL.AddCell(2, 6, []int{2, 5, 7, 9})
-	emptyL.AddCell(2, 7, []int{2, 5, 7})
+	s.mat2 = Pmat{}
+	s.mat2[0][0] = []int{1, 2, 5, 6}
+	s.mat2[0][2] = []int{1, 5, 6, 9}
+	s.mat2[0][3] = []int{2, 5, 7, 9}
+	s.mat2[0][4] = []int{5, 7, 9}
+	s.mat2[0][6] = []int{1, 2, 5, 6, 7, 9}
+	s.mat2[0][7] = []int{2, 5, 6, 7}
+	s.mat2[2][0] = []int{2, 5, 8}
+	s.mat2[2][2] = []int{5, 8, 9}
+	s.mat2[2][3] = []int{2, 5, 7, 9}
+	s.mat2[2][6] = []int{2, 5, 7, 9}
+	s.mat2[2][7] = []int{2, 5, 7}
 
-	PrintPossibleMat(mat2)
+	s.emptyL = CreatelinkedList()
+	s.emptyL.AddCell(0, 6, []int{1, 2, 5, 6, 7, 9})
+	s.emptyL.AddCell(0, 7, []int{2, 5, 6, 7})
+	s.emptyL.AddCell(2, 6, []int{2, 5, 7, 9})
+	s.emptyL.AddCell(2, 7, []int{2, 5, 7})
 
-	startCnt := CountElemPosMat(mat2)
+	PrintPossibleMat(s.mat2)
 
-	_, cnt, _ := rule20()
+	startCnt := CountElemPosMat(s.mat2)
 
-	endCnt := CountElemPosMat(mat2)
+	_, cnt, _ := s.rule20()
+
+	endCnt := CountElemPosMat(s.mat2)
 
 	if cnt != 1 {
 		t.Fatal("Should have found X-wing but not.\n")
 	}
 
-	if Contains(mat2[0][6], 2) {
+	if Contains(s.mat2[0][6], 2) {
 		t.Fatal("Possibility matrix cell [0,6] should not contain 2.\n")
 	}
 
-	if Contains(mat2[0][7], 2) {
+	if Contains(s.mat2[0][7], 2) {
 		t.Fatal("Possibility matrix cell [0,7] should not contain 2.\n")
 	}
 
-	if Contains(mat2[2][6], 2) {
+	if Contains(s.mat2[2][6], 2) {
 		t.Fatal("Possibility matrix cell [2,6] should not contain 2.\n")
 	}
 
-	if Contains(mat2[2][7], 2) {
+	if Contains(s.mat2[2][7], 2) {
 		t.Fatal("Possibility matrix cell [2,7] should not contain 2.\n")
 	}
 
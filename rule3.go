@@ -13,7 +13,7 @@ import (
 // Rule 3	Hidden singles
 //          A digit that is the only one in an entire row, column, or block.
 //          Fill in this digiti and erase any other occurrence of this digit in the same row, column or block.
-func rule3() (*Matchlist, int, time.Duration) {
+func (s *solver) rule3() (*Matchlist, int, time.Duration) {
 	var (
 		count, itercnt           int
 		foundHiddenSingle, debug bool
@@ -28,7 +28,7 @@ func rule3() (*Matchlist, int, time.Duration) {
 		itercnt = 0
 		for {
 			foundHiddenSingle = false
-			currNode := emptyL.Head
+			currNode := s.emptyL.Head
 
 			if currNode == nil {
 				color.Yellow.Println("Rule 3: Empty list.")
@@ -40,16 +40,16 @@ func rule3() (*Matchlist, int, time.Duration) {
 					}
 
 					if Contains(currNode.Vals, dig) {
-						foundHiddenSingle = findDigitAndUpdate(currNode, dig)
+						foundHiddenSingle = s.findDigitAndUpdate(currNode, dig)
 						if foundHiddenSingle {
 							matched.AddCell(currNode, dig)
 							count++
 						}
 					}
 
-					if emptyCnt <= 0 {
+					if s.emptyCnt <= 0 {
 						if debug {
-							fmt.Printf("Empty list count = %d\n", emptyL.CountNodes())
+							fmt.Printf("Empty list count = %d\n", s.emptyL.CountNodes())
 						}
 						break
 					}
@@ -66,7 +66,7 @@ func rule3() (*Matchlist, int, time.Duration) {
 		}
 
 		itercnt++
-		if emptyL.CountNodes() == 0 {
+		if s.emptyL.CountNodes() == 0 {
 			break
 		}
 	}
@@ -76,7 +76,7 @@ func rule3() (*Matchlist, int, time.Duration) {
 
 // Rule 3a	Hidden singles
 //			Search in the specified row or col or blk intersecting this Cell only
-func rule3a(row, col, dig int) (*Matchlist, int, time.Duration) {
+func (s *solver) rule3a(row, col, dig int) (*Matchlist, int, time.Duration) {
 	var (
 		count                    int
 		foundHiddenSingle, debug bool
@@ -87,26 +87,26 @@ func rule3a(row, col, dig int) (*Matchlist, int, time.Duration) {
 	start = time.Now()
 	debug = DebugFn(1)
 	matched = &Matchlist{}
-	currNode = emptyL.GetNodeFoRCell(row, col)
+	currNode = s.emptyL.GetNodeFoRCell(row, col)
 
 	if Contains(currNode.Vals, dig) {
-		foundHiddenSingle = findDigitAndUpdate(currNode, dig)
+		foundHiddenSingle = s.findDigitAndUpdate(currNode, dig)
 		if foundHiddenSingle {
 			matched.AddCell(currNode, dig)
 			count++
 		}
 	}
 
-	if emptyCnt <= 0 {
+	if s.emptyCnt <= 0 {
 		if debug {
-			fmt.Printf("Empty list count = %d\n", emptyL.CountNodes())
+			fmt.Printf("Empty list count = %d\n", s.emptyL.CountNodes())
 		}
 	}
 
 	return matched, count, time.Since(start)
 }
 
-func findDigitAndUpdate(currNode *Cell, dig int) bool {
+func (s *solver) findDigitAndUpdate(currNode *Cell, dig int) bool {
 	var (
 		row, col                     int
 		notInRow, notInCol, notInBlk bool
@@ -117,24 +117,33 @@ func findDigitAndUpdate(currNode *Cell, dig int) bool {
 	col = currNode.Col
 
 	// check that there is no occurrence in same row, col or block
-	notInRow = !FindDigitInRow(debug, mat2, row, col, dig)
-	notInCol = !FindDigitInCol(debug, mat2, row, col, dig)
-	notInBlk = !FindDigitInBlk(debug, mat2, row, col, dig)
+	notInRow = !FindDigitInRow(debug, s.mat2, row, col, dig)
+	notInCol = !FindDigitInCol(debug, s.mat2, row, col, dig)
+	notInBlk = !FindDigitInBlk(debug, s.mat2, row, col, dig)
+
+	// variants: the digit may also be hidden in an extra house, e.g. a diagonal
+	notInHouse, house := false, -1
+	for _, h := range HousesOf(row, col) {
+		if !FindDigitInHouse(debug, s.mat2, h, row, col, dig) {
+			notInHouse, house = true, h
+		}
+	}
 
-	if notInRow || notInCol || notInBlk {
+	if notInRow || notInCol || notInBlk || notInHouse {
 		found = true
-		emptyL.DelNode(currNode) // remove current Node from possibility list
-		mat[row][col] = dig      // fill in dig in resulting mat
-		mat2[row][col] = nil     // blank this cell out in possibility mat
-		emptyCnt--
+		s.beginEvent(Event{
+			Technique: TechHiddenSingle,
+			House:     hiddenHouse(row, col, notInRow, notInCol, notInBlk, house),
+		})
+		s.placeDigit(currNode, dig)
 
 		// erase any occurrence of the digit in the same row, col or block
-		findAndEraseDigit(row, col, dig, notInRow, notInCol, notInBlk)
+		s.findAndEraseDigit(row, col, dig, notInRow, notInCol, notInBlk)
 	}
 	return found
 }
 
-func findAndEraseDigit(row, col, dig int, notInRow, notInCol, notInBlk bool) {
+func (s *solver) findAndEraseDigit(row, col, dig int, notInRow, notInCol, notInBlk bool) {
 	if *debugPtr {
 		if notInRow {
 			color.LightBlue.Printf("Digit %d of cell [%d][%d] not in row %d\n", dig, row, col, row)
@@ -143,30 +152,30 @@ func findAndEraseDigit(row, col, dig int, notInRow, notInCol, notInBlk bool) {
 			color.LightBlue.Printf("Digit %d of cell [%d][%d] not in col %d\n", dig, row, col, col)
 		}
 		if notInBlk {
-			color.LightBlue.Printf("Digit %d of cell [%d][%d] not in blk [%d][%d]\n",
-				dig, row, col, row/SQ, col/SQ)
+			color.LightBlue.Printf("Digit %d of cell [%d][%d] not in blk %d\n",
+				dig, row, col, BlkOf(row, col))
 		}
 	}
 
 	if !notInRow {
-		eraseDigitFromRow(row, col, dig)
+		s.eraseDigitFromRow(row, col, dig)
 
 		if *debugPtr {
-			color.LightBlue.Printf("After deletion from row %d: %v\n", row, mat2[row])
+			color.LightBlue.Printf("After deletion from row %d: %v\n", row, s.mat2[row])
 		}
 	}
 	if !notInCol {
-		eraseDigitFromCol(row, col, dig)
+		s.eraseDigitFromCol(row, col, dig)
 
 		if *debugPtr {
-			color.LightBlue.Printf("After deletion from col %d: %v\n", col, GetColOfPossibleMat(mat2, col))
+			color.LightBlue.Printf("After deletion from col %d: %v\n", col, GetColOfPossibleMat(s.mat2, col))
 		}
 	}
 	if !notInBlk {
-		eraseDigitFromBlk(row, col, dig)
+		s.eraseDigitFromBlk(row, col, dig)
 
 		if *debugPtr {
-			color.LightBlue.Printf("After deletion from blk [%d,%d]: %v\n", row/SQ, col/SQ, GetBlkOfPossibleMat(mat2, row, col))
+			color.LightBlue.Printf("After deletion from blk %d: %v\n", BlkOf(row, col), GetBlkOfPossibleMat(s.mat2, row, col))
 		}
 	}
 	if notInRow && notInCol && notInBlk {
@@ -174,4 +183,12 @@ func findAndEraseDigit(row, col, dig int, notInRow, notInCol, notInBlk bool) {
 			color.LightBlue.Println("No deletion necessary.")
 		}
 	}
+
+	// variants: erase the digit from any extra house containing this cell
+	for _, h := range HousesOf(row, col) {
+		s.eraseDigitFromHouse(h, row, col, dig)
+	}
+
+	// variants: constraints such as killer cages may rule out more candidates
+	s.eraseByConstraints()
 }
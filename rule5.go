@@ -13,7 +13,7 @@ import (
 // Rule 5	Naked pairs
 //          A pair of digits that occurs in exactly 2 cells in an entire row, column, or block.
 //          Erase any other occurrence of these 2 digits elsewhere in the same row, column or block.
-func rule5() (*Matchlist, int, time.Duration) {
+func (s *solver) rule5() (*Matchlist, int, time.Duration) {
 	var (
 		col, row, col2, row2   int // position of last empty cell
 		count                  int
@@ -33,7 +33,7 @@ func rule5() (*Matchlist, int, time.Duration) {
 	for foundNakedPairs {
 		foundNakedPairs = false
 
-		currNode := emptyL.Head
+		currNode := s.emptyL.Head
 
 		if currNode == nil {
 			color.Yellow.Println("Rule 5: Empty list.")
@@ -50,15 +50,15 @@ func rule5() (*Matchlist, int, time.Duration) {
 					twoElem = currNode.Vals
 					// check row
 					for c := 0; c < N; c++ {
-						if IntArrayEquals(mat2[row][c], twoElem) && c != col {
+						if IntArrayEquals(s.mat2[row][c], twoElem) && c != col {
 							col2 = c
 
 							if debug {
-								PrintPossibleMat(mat2)
+								PrintPossibleMat(s.mat2)
 								color.Magenta.Printf("Found naked pair in row %d, in cols %d and %d.\n", row, col, col2)
 							}
 
-							secondNode = emptyL.GetNodeFoRCell(row, col2)
+							secondNode = s.emptyL.GetNodeFoRCell(row, col2)
 
 							arr := AddRCell(nil, currNode, secondNode)
 
@@ -68,12 +68,12 @@ func rule5() (*Matchlist, int, time.Duration) {
 								}
 
 								matched.AddRNode(arr)
-								inRow = FindDigitInRowPair(debug, mat2, row, col, col2, twoElem)
+								inRow = FindDigitInRowPair(debug, s.mat2, row, col, col2, twoElem)
 								if inRow {
 									if debug {
 										fmt.Printf("Found digits of pairs in row %d.\n", row)
 									}
-									eraseDigitsFromRowOfPairs(row, col, col2, twoElem)
+									s.eraseDigitsFromRowOfPairs(row, col, col2, twoElem)
 								}
 								foundNakedPairs = true
 								count++
@@ -88,14 +88,14 @@ func rule5() (*Matchlist, int, time.Duration) {
 
 					// check col
 					for r := 0; r < N; r++ {
-						if IntArrayEquals(mat2[r][col], twoElem) && r != row {
+						if IntArrayEquals(s.mat2[r][col], twoElem) && r != row {
 							row2 = r
 
 							if debug {
 								color.Magenta.Printf("Found naked pair in col %d, in rows %d and %d.\n", col, row, row2)
 							}
 
-							secondNode = emptyL.GetNodeFoRCell(row2, col)
+							secondNode = s.emptyL.GetNodeFoRCell(row2, col)
 
 							arr := AddRCell(nil, currNode, secondNode)
 
@@ -105,12 +105,12 @@ func rule5() (*Matchlist, int, time.Duration) {
 								}
 
 								matched.AddRNode(arr)
-								inCol = FindDigitInColPair(debug, mat2, row, col, row2, twoElem)
+								inCol = FindDigitInColPair(debug, s.mat2, row, col, row2, twoElem)
 								if inCol {
 									if debug {
 										fmt.Printf("Found digits of pairs in col %d.\n", col)
 									}
-									eraseDigitsFromColOfPairs(row, col, row2, twoElem)
+									s.eraseDigitsFromColOfPairs(row, col, row2, twoElem)
 								}
 								foundNakedPairs = true
 								count++
@@ -124,67 +124,92 @@ func rule5() (*Matchlist, int, time.Duration) {
 					}
 
 					// check blk
-					startRow := row / SQ * SQ
-					startCol := col / SQ * SQ
-					emptyCntBlk := emptyL.CountNodes()
+					blk := BlkOf(row, col)
+					emptyCntBlk := s.emptyL.CountNodes()
 
 					if debug {
 						fmt.Printf("Finding 2nd pair [%d,%d] cell [%d,%d]\n", twoElem[0], twoElem[1], row, col)
 					}
 
-					for x := startRow; x < startRow+SQ; x++ {
-						for y := startCol; y < startCol+SQ; y++ {
+					for _, cell := range BlkCells(blk) {
+						x, y := cell.Row, cell.Col
+						if debug {
+							fmt.Printf("Blk %d: cell [%d,%d]\n", blk, x, y)
+						}
+
+						if IntArrayEquals(s.mat2[x][y], twoElem) && !(x == row && y == col) {
+							row2 = x
+							col2 = y
+
 							if debug {
-								fmt.Printf("Blk [%d,%d]: cell [%d,%d]\n", row/SQ, col/SQ, x, y)
+								color.Magenta.Printf("Found naked pair (%d,%d) in blk %d, in cells [%d,%d] and [%d,%d].\n",
+									twoElem[0], twoElem[1], blk, row, col, row2, col2)
 							}
 
-							if IntArrayEquals(mat2[x][y], twoElem) && !(x == row && y == col) {
-								row2 = x
-								col2 = y
+							secondNode = s.emptyL.GetNodeFoRCell(row2, col2)
+							arr := AddRCell(nil, currNode, secondNode)
+
+							if debug {
+								matched.PrintResult("Naked pairs")
+							}
+
+							if !matched.ContainsPair(arr) {
+								matched.AddRNode(arr)
 
 								if debug {
-									color.Magenta.Printf("Found naked pair (%d,%d) in blk [%d,%d], in cells [%d,%d] and [%d,%d].\n",
-										twoElem[0], twoElem[1], row/SQ, col/SQ, row, col, row2, col2)
+									fmt.Printf("Blk %d\n", blk)
 								}
 
-								secondNode = emptyL.GetNodeFoRCell(row2, col2)
-								arr := AddRCell(nil, currNode, secondNode)
+								inBlk = FindDigitInBlkPair(debug, s.mat2, row, col, row2, col2, twoElem)
+								if inBlk {
+									if debug {
+										fmt.Printf("Found digits of pairs in blk %d.\n", blk)
+										PrintPossibleMat(s.mat2)
+									}
+
+									s.eraseDigitsFromBlkOfPairs(row, col, row2, col2, twoElem)
+								}
+								foundNakedPairs = true
+								count++
+
+								if debug {
+									fmt.Printf("Naked pairs = %d.\n", count)
+								}
+								break
+							}
 
+						}
+					}
+
+					// check extra houses of variants, e.g. diagonals
+					for _, h := range HousesOf(row, col) {
+						for _, cell := range Houses[h] {
+							x, y := cell.Row, cell.Col
+							if IntArrayEquals(s.mat2[x][y], twoElem) && !(x == row && y == col) {
 								if debug {
-									matched.PrintResult("Naked pairs")
+									color.Magenta.Printf("Found naked pair (%d,%d) in house %d, in cells [%d,%d] and [%d,%d].\n",
+										twoElem[0], twoElem[1], h, row, col, x, y)
 								}
 
+								secondNode = s.emptyL.GetNodeFoRCell(x, y)
+								arr := AddRCell(nil, currNode, secondNode)
+
 								if !matched.ContainsPair(arr) {
 									matched.AddRNode(arr)
 
-									if debug {
-										fmt.Printf("Blk [%d,%d]\n", row/SQ, col/SQ)
-									}
-
-									inBlk = FindDigitInBlkPair(debug, mat2, row, col, row2, col2, twoElem)
-									if inBlk {
-										if debug {
-											fmt.Printf("Found digits of pairs in blk [%d,%d].\n", row/SQ, col/SQ)
-											PrintPossibleMat(mat2)
-										}
-
-										eraseDigitsFromBlkOfPairs(row, col, row2, col2, twoElem)
+									if FindDigitInHousePair(debug, s.mat2, h, row, col, x, y, twoElem) {
+										s.eraseDigitsFromHouseOfPairs(h, row, col, x, y, twoElem)
 									}
 									foundNakedPairs = true
 									count++
-
-									if debug {
-										fmt.Printf("Naked pairs = %d.\n", count)
-									}
 									break
 								}
-
 							}
 						}
 					}
 
-					if emptyL.CountNodes() < emptyCntBlk {
-						color.LightMagenta.Printf("Deleted cells after checking block: %d\n", emptyCntBlk-emptyL.CountNodes())
+					if s.emptyL.CountNodes() < emptyCntBlk {
+						color.LightMagenta.Printf("Deleted cells after checking block: %d\n", emptyCntBlk-s.emptyL.CountNodes())
 					}
 				}
 
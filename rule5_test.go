@@ -148,16 +148,20 @@ func TestContainsPair(t *testing.T) {
 
 // Rule 5: Naked Pairs
 func TestRule5(t *testing.T) {
+	s := newSolver()
+
 	// mid-way through difficiult3¯
 	input := "142.73...597.462.3863.52...31852469772639.4.545976.32.6.54391.293128....2.461..39"
 
-	ruleTest(t, input, 5, 23, 10)
+	s.ruleTest(t, input, 5, 23, 10)
 
 }
 
 func TestRule5a(t *testing.T) {
+	s := newSolver()
+
 	// difficult1.txt
 	// naked pair (2,8) found in starting possibility matrix at cells [2,1] and [1,2] of block [0,0].
 
-	ruleTest(t, difficult1, 5, 51, 1)
+	s.ruleTest(t, difficult1, 5, 51, 1)
 }
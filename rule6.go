@@ -78,7 +78,7 @@ func findTripInRow(m2 Pmat) (int, bool) {
 // At least 1 cell must contain 3 digits.
 // Solution: Search for a cell with 3 digits. Then search for exactly 2 more cells which contain either 2 or 3 digits
 // where the digits are the same as that of the first 3-digit cell.
-func findTripInRowVar(m2 Pmat, row int) bool {
+func (s *solver) findTripInRowVar(m2 Pmat, row int) bool {
 	var (
 		found         bool
 		totCnt, count int
@@ -129,7 +129,7 @@ func findTripInRowVar(m2 Pmat, row int) bool {
 
 				if found {
 					// erase the same 3 digits from elsewhere in row
-					eraseDigitsFromRowMulti(i, trip, colList)
+					s.eraseDigitsFromRowMulti(i, trip, colList)
 					fmt.Println()
 				}
 			}
@@ -12,7 +12,7 @@ import (
 // Rule 8:	Hidden pairs
 //
 //
-func rule8() (*Matchlist, int, time.Duration) {
+func (s *solver) rule8() (*Matchlist, int, time.Duration) {
 	var (
 		count int
 		col, row/*, col2, row2*/ int
@@ -27,7 +27,7 @@ func rule8() (*Matchlist, int, time.Duration) {
 	for foundHiddenPairs {
 		foundHiddenPairs = false
 
-		currNode := emptyL.Head
+		currNode := s.emptyL.Head
 
 		if currNode == nil {
 			color.Yellow.Println("Rule 8: Empty list.")
@@ -0,0 +1,27 @@
+package main
+
+import (
+	. "github.com/mjwong/sudoku2/lib"
+	. "github.com/mjwong/sudoku2/linkedlist"
+)
+
+// State of the solve of a puzzle. The rules are methods of the solver, so puzzles can be
+// solved at the same time, each with its own solver.
+type solver struct {
+	mat      Intmat
+	mat2     Pmat   // matrix with possible values in empty cells
+	mat3     Intmat // guessed matrix
+	emptyL   *LinkedList
+	emptyCnt int
+	iterCnt  int
+
+	explaining bool    // record the steps of the solve as events
+	events     []Event // steps recorded so far, the last one is open
+
+	history []operation // operations done, the latest last
+	undone  []operation // operations undone which can be redone, the latest last
+}
+
+func newSolver() *solver {
+	return &solver{}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"testing"
+
+	. "github.com/mjwong/sudoku2/lib"
+)
+
+var (
+	x1             = "..4.2........873.4...........5.......3....1..........9.42......19....7.....7.3..." // x1.txt
+	x1Sol          = "384621597926587314517439862875914236439256178261378459742195683193862745658743921"
+	windoku1       = ".....1...92.4....8......6........7...9.3.......82..3.......8.........4.345......." // windoku1.txt
+	windoku1Sol    = "384621597926475138517839624235914786691387245748256319163548972879162453452793861"
+	centredot1     = "3......97...58.3...174.....6.....1.8.758......9.1......6..45...4......56...2....." // centredot1.txt
+	centredot1Sol  = "384621597926587314517439862642953178175862943893174625269345781431798256758216439"
+	antiknight1    = ".........6.2..4.....7.9.........2.........5...4........1......4.2....7...8....1.." // antiknight1.txt
+	antiknight1Sol = "134257698692814357857693412571932846269148573348576921715369284923481765486725139"
+	antiking1      = ".3..5...........73......4.14..613............2..8.....675..9.....9...........5..9" // antiking1.txt
+	antiking1Sol   = "134257698586941273927386451458613927763592814291874536675439182349128765812765349"
+	nonconsec1     = "1.93.4..57.5..29.....7.5...2..........39..25...62..814..14....6...8..139..8.31..." // nonconsec1.txt
+	nonconsec1Sol  = "169384725735162948482795361247518693813946257596273814371429586624857139958631472"
+)
+
+func TestSetVariants(t *testing.T) {
+	defer ClearVariants()
+
+	if err := SetVariants("x,windoku"); err != nil {
+		t.Fatal(err)
+	}
+	if len(Houses) != 6 {
+		t.Fatalf("Expected 6 extra houses but got %d.\n", len(Houses))
+	}
+
+	// centre cell is on both diagonals but in no window
+	if len(HousesOf(4, 4)) != 2 {
+		t.Fatalf("Expected cell [4,4] in 2 houses but got %v.\n", HousesOf(4, 4))
+	}
+
+	// [2,2] is on the main diagonal and in the top left window
+	if len(HousesOf(2, 2)) != 2 {
+		t.Fatalf("Expected cell [2,2] in 2 houses but got %v.\n", HousesOf(2, 2))
+	}
+
+	if err := SetVariants("x,foo"); err == nil {
+		t.Fatal("Expected error for unknown variant.")
+	}
+}
+
+func TestVariants(t *testing.T) {
+	s := newSolver()
+
+	defer ClearVariants()
+
+	tests := []struct {
+		variant, input, sol string
+	}{
+		{"x", x1, x1Sol},
+		{"windoku", windoku1, windoku1Sol},
+		{"centredot", centredot1, centredot1Sol},
+		{"antiknight", antiknight1, antiknight1Sol},
+		{"antiking", antiking1, antiking1Sol},
+		{"nonconsec", nonconsec1, nonconsec1Sol},
+	}
+
+	for _, tc := range tests {
+		if err := SetVariants(tc.variant); err != nil {
+			t.Fatal(err)
+		}
+
+		s.PrepPmat(tc.input)
+		s.RuleLoop(s.rule3, RuleTable[3], Zero)
+		s.RuleLoop(s.rule1, RuleTable[1], Zero)
+		s.RuleLoop(s.rule5, RuleTable[5], SameCnt)
+
+		s.mat3 = s.mat
+		s.iterMat(s.emptyL.Head)
+
+		if MatToString(s.mat3) != tc.sol {
+			t.Fatalf("%s: expected %s but got %s.\n", tc.variant, tc.sol, MatToString(s.mat3))
+		}
+
+		if !CheckSums(s.mat3) {
+			t.Fatalf("%s: sums of extra houses are wrong.\n", tc.variant)
+		}
+	}
+}
+
+// On a diagonal, the digit given at [0,0] must not be a candidate of [8,8]
+func TestDiagonalCandidates(t *testing.T) {
+	s := newSolver()
+
+	defer ClearVariants()
+
+	input := "1................................................................................"
+	SetVariants("x")
+	s.PrepPmat(input)
+
+	if Contains(s.mat2[8][8], 1) {
+		t.Fatalf("Cell [8,8] should not contain 1 but got %v.\n", s.mat2[8][8])
+	}
+	if !Contains(s.mat2[8][7], 1) {
+		t.Fatalf("Cell [8,7] should contain 1 but got %v.\n", s.mat2[8][7])
+	}
+}
+
+// Givens repeated in an extra house leave no solution, and the search keeps to the houses
+func TestHouseSolutions(t *testing.T) {
+	defer ClearVariants()
+	SetVariants("x")
+
+	m := PopulateMat(x1)
+	if sol, cnt := SolveUnique(m); cnt != 1 || MatToString(sol) != x1Sol {
+		t.Fatalf("Expected the solution %s but got %d solutions.\n", x1Sol, cnt)
+	}
+
+	var clash Intmat
+	clash[0][0], clash[8][8] = 1, 1
+	if cnt := CountSolutions(clash, 2); cnt != 0 {
+		t.Fatalf("Expected no solution with 1 twice on the diagonal but got %d.\n", cnt)
+	}
+}
+
+// Chess variants rule out candidates a knight's or king's move away and consecutive neighbours
+func TestChessCandidates(t *testing.T) {
+	s := newSolver()
+
+	defer ClearVariants()
+
+	input := "5................................................................................"
+	SetVariants("antiknight,antiking,nonconsec")
+	s.PrepPmat(input)
+
+	if Contains(s.mat2[1][2], 5) || Contains(s.mat2[2][1], 5) {
+		t.Fatalf("Cells a knight's move from [0,0] should not contain 5: %v %v.\n", s.mat2[1][2], s.mat2[2][1])
+	}
+	if Contains(s.mat2[1][1], 5) {
+		t.Fatalf("Cell a king's move from [0,0] should not contain 5: %v.\n", s.mat2[1][1])
+	}
+	if ContainsMulti(s.mat2[0][1], []int{4, 6}) || ContainsMulti(s.mat2[1][0], []int{4, 6}) {
+		t.Fatalf("Neighbours of [0,0] should not contain 4 or 6: %v %v.\n", s.mat2[0][1], s.mat2[1][0])
+	}
+	if !Contains(s.mat2[3][3], 5) {
+		t.Fatalf("Cell [3,3] should contain 5 but got %v.\n", s.mat2[3][3])
+	}
+
+	var m Intmat
+	m[0][0] = 5
+	if IsSafe(m, 2, 1, 5) || IsSafe(m, 0, 1, 6) || !IsSafe(m, 0, 1, 7) {
+		t.Fatal("IsSafe does not apply the chess constraints.")
+	}
+}